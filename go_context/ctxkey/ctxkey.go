@@ -0,0 +1,76 @@
+// Package ctxkey provides context keys that can not collide by accident.
+//
+// Plain string keys like "keyA" are equal whenever their contents are equal,
+// so two unrelated parts of a program may shadow each other's values. A Key
+// built by NewKey is only ever equal to itself.
+package ctxkey
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// Key is a context key. Its fields are unexported, so a Key can only be
+// built by NewKey.
+type Key struct {
+	name string
+	id   uint64
+}
+
+var nextID uint64
+
+// NewKey returns a new Key. Every call returns a distinct key, even when
+// the names match.
+func NewKey(name string) Key {
+	return Key{name: name, id: atomic.AddUint64(&nextID, 1)}
+}
+
+// Name returns the name the key was created with.
+func (k Key) Name() string {
+	return k.name
+}
+
+// String implements fmt.Stringer.
+func (k Key) String() string {
+	return k.name
+}
+
+// WithValue is context.WithValue restricted to Key.
+func WithValue(ctx context.Context, key Key, val any) context.Context {
+	return context.WithValue(ctx, key, val)
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = make(map[string]struct{})
+)
+
+// Register returns a new Key named name and records the name, panicking if
+// it has been registered before. Registering keys from package level vars
+// turns a clash of names into a failure at startup, instead of one value
+// silently shadowing another at runtime.
+func Register(name string) Key {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, dup := registry[name]; dup {
+		panic(fmt.Sprintf("ctxkey: key %q registered twice", name))
+	}
+	registry[name] = struct{}{}
+	return NewKey(name)
+}
+
+// MustRegister is Register. The name only makes the panic explicit at the
+// call site.
+func MustRegister(name string) Key {
+	return Register(name)
+}
+
+// IsRegistered reports whether name has been registered.
+func IsRegistered(name string) bool {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	_, ok := registry[name]
+	return ok
+}
@@ -0,0 +1,59 @@
+package ctxkey
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// TestNoCollision rebuilds the keyA/keyC scenario of main.go with Keys of
+// the same name.
+func TestNoCollision(t *testing.T) {
+	ctx := context.Background()
+
+	keyA := NewKey("keyA")
+	ctxA := WithValue(ctx, keyA, "value from ctxA")
+
+	keyC := NewKey("keyA") // same name as keyA
+	ctxC := WithValue(ctxA, keyC, "value from ctxC")
+
+	if keyA == keyC {
+		t.Fatal("keys with the same name are equal")
+	}
+	if got := ctxC.Value(keyA); got != "value from ctxA" {
+		t.Errorf("ctxC.Value(keyA) = %v, want value from ctxA", got)
+	}
+	if got := ctxC.Value(keyC); got != "value from ctxC" {
+		t.Errorf("ctxC.Value(keyC) = %v, want value from ctxC", got)
+	}
+	if got := ctxC.Value("keyA"); got != nil {
+		t.Errorf("ctxC.Value(\"keyA\") = %v, want nil", got)
+	}
+}
+
+func TestName(t *testing.T) {
+	k := NewKey("keyA")
+	if k.Name() != "keyA" || k.String() != "keyA" {
+		t.Errorf("Name() = %q, String() = %q, want keyA", k.Name(), k.String())
+	}
+}
+
+func TestRegister(t *testing.T) {
+	// the registry is global, so every run needs a name of its own
+	name := fmt.Sprintf("ctxkey_test.register.%d", NewKey("").id)
+	if IsRegistered(name) {
+		t.Fatalf("%s registered before Register", name)
+	}
+	k := Register(name)
+	if k.Name() != name || !IsRegistered(name) {
+		t.Fatalf("Register(%q) = %v, IsRegistered = %v", name, k, IsRegistered(name))
+	}
+
+	defer func() {
+		want := fmt.Sprintf("ctxkey: key %q registered twice", name)
+		if got := recover(); got != want {
+			t.Errorf("second Register panic = %v, want %s", got, want)
+		}
+	}()
+	MustRegister(name)
+}
@@ -0,0 +1,132 @@
+// Package ctxleak reports contexts whose CancelFunc was never called.
+//
+// Forgetting to call cancel keeps the child registered in its parent's
+// children set until the parent itself is canceled. TrackCancel records
+// where each context was created, and a finalizer logs a warning if the
+// context is garbage-collected before cancel is called.
+package ctxleak
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// LeakInfo describes a tracked context whose cancel func has not been
+// called.
+type LeakInfo struct {
+	Created time.Time
+	Stack   string // where TrackCancel was called
+
+	// Collected is true if the context has already been garbage-collected,
+	// so cancel can never be called any more.
+	Collected bool
+}
+
+var (
+	enabled atomic.Bool
+
+	mu      sync.Mutex
+	nextID  uint64
+	records = make(map[uint64]*LeakInfo)
+)
+
+func init() {
+	enabled.Store(true)
+}
+
+// SetEnabled turns tracking on or off. While disabled, TrackCancel is
+// plain context.WithCancel. Contexts tracked before the call are still
+// reported.
+func SetEnabled(on bool) {
+	enabled.Store(on)
+}
+
+// trackedCtx is what TrackCancel hands out. The finalizer is set on it
+// rather than on the inner cancelCtx, which may be kept alive by its
+// parent's children set long after the caller dropped it.
+type trackedCtx struct {
+	context.Context
+}
+
+// TrackCancel is like context.WithCancel, but remembers the context until
+// the returned cancel is called.
+//
+// Only a record with the creation stack is kept, never the context, so
+// tracking does not keep it alive. Leaks are noticed once the garbage
+// collector runs.
+func TrackCancel(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	if !enabled.Load() {
+		return ctx, cancel
+	}
+
+	info := &LeakInfo{Created: time.Now(), Stack: callers(2)}
+	mu.Lock()
+	nextID++
+	id := nextID
+	records[id] = info
+	mu.Unlock()
+
+	c := &trackedCtx{ctx}
+	runtime.SetFinalizer(c, func(c *trackedCtx) {
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := records[id]; !ok {
+			return
+		}
+		if c.Err() != nil {
+			// canceled through its parent, nothing is left behind
+			delete(records, id)
+			return
+		}
+		info.Collected = true
+		log.Printf("ctxleak: context garbage-collected without calling cancel, created at:\n%s", info.Stack)
+	})
+
+	// the closure must not capture c, or holding on to cancel would keep
+	// the finalizer from ever running
+	return c, func() {
+		mu.Lock()
+		delete(records, id)
+		mu.Unlock()
+		cancel()
+	}
+}
+
+// ReportLeaks returns every tracked context whose cancel has not been
+// called yet, oldest first.
+func ReportLeaks() []LeakInfo {
+	mu.Lock()
+	leaks := make([]LeakInfo, 0, len(records))
+	for _, info := range records {
+		leaks = append(leaks, *info)
+	}
+	mu.Unlock()
+
+	sort.Slice(leaks, func(i, j int) bool { return leaks[i].Created.Before(leaks[j].Created) })
+	return leaks
+}
+
+// callers formats the stack of the caller skip frames above callers.
+func callers(skip int) string {
+	pc := make([]uintptr, 32)
+	n := runtime.Callers(skip+1, pc)
+	frames := runtime.CallersFrames(pc[:n])
+
+	var b strings.Builder
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,115 @@
+// Package ctxlog carries logging fields in a context. Fields attached to a
+// parent are inherited by its children, the same way a valueCtx lookup
+// walks up to its ancestors.
+package ctxlog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Field is a single key/value pair written with every log line.
+type Field struct {
+	Key   string
+	Value any
+}
+
+// F is shorthand for Field{Key: key, Value: value}.
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+type ctxKey struct{}
+
+// state is what a context carries under ctxKey. It is never modified once
+// stored, With and WithWriter store a copy.
+type state struct {
+	w      io.Writer
+	fields []Field
+}
+
+func stateFrom(ctx context.Context) state {
+	s, _ := ctx.Value(ctxKey{}).(state)
+	return s
+}
+
+// With returns a child of ctx whose logger has fields in addition to the
+// ones inherited from ctx. A field whose key is already inherited replaces
+// the inherited one.
+func With(ctx context.Context, fields ...Field) context.Context {
+	s := stateFrom(ctx)
+	merged := make([]Field, len(s.fields), len(s.fields)+len(fields))
+	copy(merged, s.fields)
+next:
+	for _, f := range fields {
+		for i := range merged {
+			if merged[i].Key == f.Key {
+				merged[i] = f
+				continue next
+			}
+		}
+		merged = append(merged, f)
+	}
+	s.fields = merged
+	return context.WithValue(ctx, ctxKey{}, s)
+}
+
+// WithWriter returns a child of ctx whose logger writes to w. Without it
+// loggers write to os.Stderr.
+func WithWriter(ctx context.Context, w io.Writer) context.Context {
+	s := stateFrom(ctx)
+	s.w = w
+	return context.WithValue(ctx, ctxKey{}, s)
+}
+
+// Logger writes JSON lines with the fields it was created with.
+type Logger struct {
+	w      io.Writer
+	fields []Field
+}
+
+// From returns a logger holding every field attached to ctx and its
+// ancestors.
+func From(ctx context.Context) *Logger {
+	s := stateFrom(ctx)
+	if s.w == nil {
+		s.w = os.Stderr
+	}
+	return &Logger{w: s.w, fields: s.fields}
+}
+
+// Info writes msg and the logger's fields as a single JSON object.
+func (l *Logger) Info(msg string) {
+	l.log("info", msg)
+}
+
+func (l *Logger) log(level, msg string) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	writePair(&buf, "level", level)
+	buf.WriteByte(',')
+	writePair(&buf, "msg", msg)
+	for _, f := range l.fields {
+		buf.WriteByte(',')
+		writePair(&buf, f.Key, f.Value)
+	}
+	buf.WriteString("}\n")
+	l.w.Write(buf.Bytes())
+}
+
+// writePair writes "key":value. Values that json can not encode are
+// written as their fmt representation instead.
+func writePair(buf *bytes.Buffer, key string, value any) {
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteByte(':')
+	v, err := json.Marshal(value)
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(v)
+}
@@ -0,0 +1,68 @@
+// Package ctxmetrics counts why contexts end: an explicit cancel, a
+// deadline, or the cancellation of a parent.
+package ctxmetrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// CancelStats counts how the contexts of one label ended.
+type CancelStats struct {
+	Explicit int // the CancelFunc from TrackedCancel was called
+	Deadline int // a deadline passed
+	Parent   int // an ancestor was canceled
+}
+
+// errExplicit is the cause set by the CancelFunc of TrackedCancel, so it
+// can be told apart from a parent that was canceled with no cause.
+var errExplicit = fmt.Errorf("ctxmetrics: %w", context.Canceled)
+
+var (
+	mu    sync.Mutex
+	stats = make(map[string]CancelStats)
+)
+
+// TrackedCancel is like context.WithCancel, but records under label how
+// the returned context ended. The record is made by a goroutine that exits
+// as soon as the context is done, so the CancelFunc must be called as
+// usual.
+//
+// After an explicit cancel, context.Cause reports an error wrapping
+// context.Canceled rather than context.Canceled itself.
+func TrackedCancel(parent context.Context, label string) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	go func() {
+		<-ctx.Done()
+		record(label, ctx)
+	}()
+	return ctx, func() { cancel(errExplicit) }
+}
+
+func record(label string, ctx context.Context) {
+	mu.Lock()
+	defer mu.Unlock()
+	s := stats[label]
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		s.Deadline++
+	case context.Cause(ctx) == errExplicit:
+		s.Explicit++
+	default:
+		s.Parent++
+	}
+	stats[label] = s
+}
+
+// Stats returns a copy of the counts recorded so far, by label.
+func Stats() map[string]CancelStats {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make(map[string]CancelStats, len(stats))
+	for label, s := range stats {
+		out[label] = s
+	}
+	return out
+}
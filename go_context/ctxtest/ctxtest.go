@@ -0,0 +1,52 @@
+// Package ctxtest provides test assertions about when and why a context is
+// done.
+package ctxtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// AssertDoneWithin fails t if ctx is not done within d. It returns as soon
+// as ctx is done, and never waits longer than d.
+func AssertDoneWithin(t testing.TB, ctx context.Context, d time.Duration) {
+	t.Helper()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+		t.Errorf("context not done within %v", d)
+	}
+}
+
+// AssertNotDone fails t if ctx is done within d. It waits the whole of d
+// unless ctx is done sooner.
+func AssertNotDone(t testing.TB, ctx context.Context, d time.Duration) {
+	t.Helper()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		t.Errorf("context done within %v: %v", d, context.Cause(ctx))
+	case <-timer.C:
+	}
+}
+
+// AssertCause fails t unless context.Cause(ctx) matches want, as tested by
+// errors.Is. A nil want asserts that ctx is not done.
+func AssertCause(t testing.TB, ctx context.Context, want error) {
+	t.Helper()
+	got := context.Cause(ctx)
+	if want == nil {
+		if got != nil {
+			t.Errorf("context cause = %v, want nil", got)
+		}
+		return
+	}
+	if !errors.Is(got, want) {
+		t.Errorf("context cause = %v, want %v", got, want)
+	}
+}
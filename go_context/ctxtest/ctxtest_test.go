@@ -0,0 +1,92 @@
+package ctxtest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// fakeTB records whether Errorf was called. Methods the helpers do not use
+// panic through the nil embedded TB.
+type fakeTB struct {
+	testing.TB
+	failed bool
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.failed = true
+}
+
+func TestAssertDoneWithin(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	var tb fakeTB
+	AssertDoneWithin(&tb, ctx, time.Second)
+	if tb.failed {
+		t.Error("failed for a context done in time")
+	}
+
+	long, cancelLong := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelLong()
+
+	tb = fakeTB{}
+	start := time.Now()
+	AssertDoneWithin(&tb, long, 10*time.Millisecond)
+	if !tb.failed {
+		t.Error("passed for a context not done in time")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("blocked for %v", elapsed)
+	}
+}
+
+func TestAssertNotDone(t *testing.T) {
+	long, cancelLong := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelLong()
+
+	var tb fakeTB
+	AssertNotDone(&tb, long, 10*time.Millisecond)
+	if tb.failed {
+		t.Error("failed for a context that is not done")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	tb = fakeTB{}
+	AssertNotDone(&tb, ctx, time.Second)
+	if !tb.failed {
+		t.Error("passed for a context that is done")
+	}
+}
+
+func TestAssertCause(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		want   error
+		failed bool
+	}{
+		{"deadline", ctx, context.DeadlineExceeded, false},
+		{"wrong cause", ctx, errBoom, true},
+		{"not done", context.Background(), nil, false},
+		{"done but want nil", ctx, nil, true},
+	}
+	for _, tt := range tests {
+		var tb fakeTB
+		AssertCause(&tb, tt.ctx, tt.want)
+		if tb.failed != tt.failed {
+			t.Errorf("%s: failed = %v, want %v", tt.name, tb.failed, tt.failed)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrCyclic is returned by CheckAcyclic when a chain loops back on
+	// itself.
+	ErrCyclic = errors.New("ctxutil: context chain has a cycle")
+
+	// ErrTooDeep is returned by CheckAcyclic when a chain is deeper than
+	// the allowed maximum.
+	ErrTooDeep = errors.New("ctxutil: context chain too deep")
+)
+
+type acyclicConfig struct {
+	maxDepth int
+}
+
+// AcyclicOption configures CheckAcyclic.
+type AcyclicOption func(*acyclicConfig)
+
+// WithMaxDepth sets the depth past which CheckAcyclic gives up, 10000 by
+// default.
+func WithMaxDepth(n int) AcyclicOption {
+	return func(c *acyclicConfig) { c.maxDepth = n }
+}
+
+// CheckAcyclic walks the parent chain of ctx and returns an error wrapping
+// ErrCyclic if it reaches a node twice, or ErrTooDeep if it goes past the
+// maximum depth. Such a chain can only come from a broken custom context
+// type, but would make every Value lookup loop forever.
+//
+// The nodes of shallow chains are remembered in a fixed size array; only
+// deeper chains need a map, whose size is bounded by the maximum depth.
+func CheckAcyclic(ctx context.Context, opts ...AcyclicOption) error {
+	cfg := acyclicConfig{maxDepth: 10000}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var (
+		small [32]uintptr
+		n     int
+		big   map[uintptr]struct{}
+		err   error
+	)
+	seen := func(p uintptr) bool {
+		for _, q := range small[:n] {
+			if q == p {
+				return true
+			}
+		}
+		if _, ok := big[p]; ok {
+			return true
+		}
+		if n < len(small) {
+			small[n] = p
+			n++
+			return false
+		}
+		if big == nil {
+			big = make(map[uintptr]struct{})
+		}
+		big[p] = struct{}{}
+		return false
+	}
+
+	walk(ctx, func(depth int, l layer) bool {
+		if depth > cfg.maxDepth {
+			err = fmt.Errorf("%w: more than %d layers", ErrTooDeep, cfg.maxDepth)
+			return false
+		}
+		// only pointers can be shared between layers, so only they can
+		// close a loop
+		if v := reflect.ValueOf(l.ctx); v.Kind() == reflect.Pointer && seen(v.Pointer()) {
+			err = fmt.Errorf("%w: %T reached again at depth %d", ErrCyclic, l.ctx, depth)
+			return false
+		}
+		return true
+	})
+	return err
+}
@@ -0,0 +1,51 @@
+package ctxutil
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+const (
+	afterFuncPending int32 = iota
+	afterFuncRunning
+	afterFuncStopped
+)
+
+// AfterFunc arranges for fn to run in its own goroutine once ctx is done.
+// If ctx is already done, fn is scheduled right away. It works like
+// context.AfterFunc from go1.21, written out for study.
+//
+// Calling stop before fn has started prevents it from running and returns
+// true. Once fn has started, or after a previous stop, it returns false.
+// stop does not wait for fn to finish.
+//
+// A ctx that can never be done, like context.Background(), gets no watcher
+// goroutine at all.
+func AfterFunc(ctx context.Context, fn func()) (stop func() bool) {
+	var state atomic.Int32
+	done := ctx.Done()
+	if done == nil {
+		return func() bool {
+			return state.CompareAndSwap(afterFuncPending, afterFuncStopped)
+		}
+	}
+	stopped := make(chan struct{})
+
+	go func() {
+		select {
+		case <-done:
+			if state.CompareAndSwap(afterFuncPending, afterFuncRunning) {
+				fn()
+			}
+		case <-stopped:
+		}
+	}()
+
+	return func() bool {
+		if !state.CompareAndSwap(afterFuncPending, afterFuncStopped) {
+			return false
+		}
+		close(stopped) // let the watcher exit
+		return true
+	}
+}
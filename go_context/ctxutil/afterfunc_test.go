@@ -0,0 +1,59 @@
+package ctxutil
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestAfterFuncDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ran := make(chan struct{})
+	stop := AfterFunc(ctx, func() { close(ran) })
+	select {
+	case <-ran:
+	case <-time.After(time.Second):
+		t.Fatal("fn did not run for a done context")
+	}
+	if stop() {
+		t.Error("stop() = true after fn ran")
+	}
+}
+
+func TestAfterFuncStop(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ran := make(chan struct{})
+	stop := AfterFunc(ctx, func() { close(ran) })
+	if !stop() {
+		t.Fatal("first stop() = false before cancel")
+	}
+	if stop() {
+		t.Error("second stop() = true")
+	}
+
+	cancel()
+	select {
+	case <-ran:
+		t.Error("fn ran after stop")
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestAfterFuncNeverDone(t *testing.T) {
+	before := runtime.NumGoroutine()
+	stop := AfterFunc(context.Background(), func() { t.Error("fn ran") })
+	if n := runtime.NumGoroutine(); n != before {
+		t.Errorf("NumGoroutine = %d, want %d", n, before)
+	}
+	if !stop() {
+		t.Error("first stop() = false")
+	}
+	if stop() {
+		t.Error("second stop() = true")
+	}
+}
@@ -0,0 +1,21 @@
+package ctxutil
+
+import "context"
+
+// BindChannel returns a child of parent that is also canceled, with
+// context.Canceled, once done is closed. It bridges APIs that only offer a
+// done channel.
+//
+// The goroutine watching done exits as soon as either source fires or the
+// returned CancelFunc is called.
+func BindChannel(parent context.Context, done <-chan struct{}) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	go func() {
+		select {
+		case <-done:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
@@ -0,0 +1,28 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBindChannel(t *testing.T) {
+	done := make(chan struct{})
+	ctx, cancel := BindChannel(context.Background(), done)
+	defer cancel()
+
+	if ctx.Err() != nil {
+		t.Fatalf("Err() = %v before done was closed", ctx.Err())
+	}
+	close(done)
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("ctx not done after done was closed")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", ctx.Err())
+	}
+}
@@ -0,0 +1,35 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrChannelClosed is returned by Recv when the channel has been closed.
+var ErrChannelClosed = errors.New("ctxutil: channel closed")
+
+// Recv receives a value from ch, or gives up once ctx is done and returns
+// ctx.Err(). If ch is closed it returns ErrChannelClosed.
+func Recv[T any](ctx context.Context, ch <-chan T) (T, error) {
+	select {
+	case v, ok := <-ch:
+		if !ok {
+			return v, ErrChannelClosed
+		}
+		return v, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Send sends v on ch, or gives up once ctx is done and returns ctx.Err().
+// Like a plain send, it panics if ch is closed.
+func Send[T any](ctx context.Context, ch chan<- T, v T) error {
+	select {
+	case ch <- v:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
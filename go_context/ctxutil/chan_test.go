@@ -0,0 +1,49 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRecv(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ch := make(chan int, 1)
+	ch <- 42
+	if v, err := Recv(context.Background(), ch); v != 42 || err != nil {
+		t.Errorf("Recv = %d, %v, want 42, nil", v, err)
+	}
+	if _, err := Recv(canceled, ch); !errors.Is(err, context.Canceled) {
+		t.Errorf("Recv with a canceled ctx = %v, want context.Canceled", err)
+	}
+	close(ch)
+	if _, err := Recv(context.Background(), ch); err != ErrChannelClosed {
+		t.Errorf("Recv on a closed channel = %v, want ErrChannelClosed", err)
+	}
+}
+
+func TestSend(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ch := make(chan int, 1)
+	if err := Send(context.Background(), ch, 42); err != nil {
+		t.Errorf("Send = %v, want nil", err)
+	}
+	if v := <-ch; v != 42 {
+		t.Errorf("received %d, want 42", v)
+	}
+	if err := Send(canceled, make(chan int), 42); !errors.Is(err, context.Canceled) {
+		t.Errorf("Send with a canceled ctx = %v, want context.Canceled", err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Send on a closed channel did not panic")
+		}
+	}()
+	close(ch)
+	Send(context.Background(), ch, 42)
+}
@@ -0,0 +1,68 @@
+package ctxutil
+
+import (
+	"context"
+	"sync"
+)
+
+// CleanupSet holds callbacks to run once its context is done.
+type CleanupSet struct {
+	cancel context.CancelFunc
+	done   chan struct{} // closed once the callbacks have finished
+
+	mu  sync.Mutex
+	fns []func()
+	ran bool
+}
+
+// WithCleanup returns a child of parent and an empty CleanupSet bound to it.
+// The callbacks of the set run, last added first, when the child is done or
+// when Close is called. A single goroutine watches the child no matter how
+// many callbacks are added.
+func WithCleanup(parent context.Context) (context.Context, *CleanupSet) {
+	ctx, cancel := context.WithCancel(parent)
+	s := &CleanupSet{cancel: cancel, done: make(chan struct{})}
+	go func() {
+		<-ctx.Done()
+		s.run()
+	}()
+	return ctx, s
+}
+
+// Add registers fn. If the set has already run, fn is called right away.
+func (s *CleanupSet) Add(fn func()) {
+	s.mu.Lock()
+	if !s.ran {
+		s.fns = append(s.fns, fn)
+		s.mu.Unlock()
+		return
+	}
+	s.mu.Unlock()
+	fn()
+}
+
+// Close cancels the set's context and returns once the callbacks have
+// finished, even when cancellation started them first. Every callback runs
+// exactly once, however cancellation and Close race.
+func (s *CleanupSet) Close() {
+	s.cancel()
+	s.run()
+	<-s.done
+}
+
+func (s *CleanupSet) run() {
+	s.mu.Lock()
+	if s.ran {
+		s.mu.Unlock()
+		return
+	}
+	s.ran = true
+	fns := s.fns
+	s.fns = nil
+	s.mu.Unlock()
+
+	defer close(s.done)
+	for i := len(fns) - 1; i >= 0; i-- {
+		fns[i]()
+	}
+}
@@ -0,0 +1,82 @@
+package ctxutil
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCleanup(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, s := WithCleanup(parent)
+
+	var mu sync.Mutex
+	var order []int
+	last := make(chan struct{})
+	s.Add(func() { // added first, so runs last
+		mu.Lock()
+		order = append(order, 0)
+		mu.Unlock()
+		close(last)
+	})
+	for i := 1; i < 3; i++ {
+		s.Add(func() {
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		})
+	}
+
+	select {
+	case <-last:
+	case <-time.After(time.Second):
+		t.Fatal("callbacks did not run after the parent timed out")
+	}
+	s.Close() // must not run them again
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []int{2, 1, 0}
+	if len(order) != len(want) {
+		t.Fatalf("ran %v, want %v", order, want)
+	}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("ran %v, want %v", order, want)
+		}
+	}
+}
+
+func TestCleanupAddAfterRun(t *testing.T) {
+	_, s := WithCleanup(context.Background())
+	s.Close()
+
+	ran := false
+	s.Add(func() { ran = true })
+	if !ran {
+		t.Error("Add after Close did not run fn")
+	}
+}
+
+func TestCleanupCloseWaits(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	_, s := WithCleanup(parent)
+
+	started := make(chan struct{})
+	var finished atomic.Bool
+	s.Add(func() {
+		close(started)
+		time.Sleep(10 * time.Millisecond)
+		finished.Store(true)
+	})
+
+	cancel()
+	<-started // the watcher is running the callbacks
+	s.Close()
+	if !finished.Load() {
+		t.Error("Close returned before the callbacks finished")
+	}
+}
@@ -0,0 +1,56 @@
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// Debounce returns a trigger func. Each call to trigger restarts a timer of
+// d, and fn runs once the timer fires with no trigger in between. Triggers
+// after that start a new round.
+//
+// Once ctx is done the pending call, if any, is dropped, the goroutine
+// behind Debounce exits, and trigger does nothing.
+func Debounce(ctx context.Context, d time.Duration, fn func()) (trigger func()) {
+	triggers := make(chan struct{}, 1)
+
+	go func() {
+		t := time.NewTimer(d)
+		stopTimer(t)
+		defer t.Stop()
+
+		var fire <-chan time.Time // nil until the first trigger
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-triggers:
+				stopTimer(t)
+				t.Reset(d)
+				fire = t.C
+			case <-fire:
+				fire = nil
+				fn()
+			}
+		}
+	}()
+
+	return func() {
+		select {
+		case triggers <- struct{}{}:
+		default:
+			// a trigger is already queued and will restart the timer
+		}
+	}
+}
+
+// stopTimer stops t and drains a tick that was already sent, so that a
+// following Reset does not fire early.
+func stopTimer(t *time.Timer) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package ctxutil
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDebounce(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	trigger := Debounce(ctx, 20*time.Millisecond, func() { calls.Add(1) })
+	for i := 0; i < 5; i++ {
+		trigger()
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	if n := calls.Load(); n != 1 {
+		t.Errorf("fn ran %d times after rapid triggers, want 1", n)
+	}
+}
+
+func TestDebounceCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	var calls atomic.Int32
+	trigger := Debounce(ctx, 20*time.Millisecond, func() { calls.Add(1) })
+	trigger()
+	time.Sleep(5 * time.Millisecond)
+	cancel() // within the window
+
+	time.Sleep(60 * time.Millisecond)
+	if n := calls.Load(); n != 0 {
+		t.Errorf("fn ran %d times after cancel, want 0", n)
+	}
+	trigger() // does nothing, and does not block
+}
@@ -0,0 +1,38 @@
+package ctxutil
+
+import "context"
+
+// Depth returns how many layers wrap the root of ctx's chain, so
+// context.Background() itself is 0. Value, cancel and timeout nodes all
+// count as one layer each.
+//
+// Every Value lookup on a key that is missing walks all of these layers.
+func Depth(ctx context.Context) int {
+	n := -1
+	walk(ctx, func(depth int, _ layer) bool {
+		n = depth
+		return true
+	})
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// Source reports how many hops above ctx the value for key is stored, 0
+// meaning ctx itself, the way Value would resolve it. Values stored by
+// WithLazy or WithFields and looked up through MergeContexts or
+// WithFallback are found too. For a missing key it returns -1 and false.
+func Source(ctx context.Context, key any) (depth int, found bool) {
+	depth = -1
+	lookup(ctx, func(d int, l layer) bool {
+		return l.pairs(func(p pair) bool {
+			if p.key == key {
+				depth, found = d, true
+				return false
+			}
+			return true
+		})
+	})
+	return depth, found
+}
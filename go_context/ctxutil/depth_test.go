@@ -0,0 +1,107 @@
+package ctxutil
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// mainChain builds the chain of main.go.
+func mainChain() (ctxB, ctxD context.Context) {
+	ctx := context.Background()
+
+	keyA := "keyA"
+	ctxA := context.WithValue(ctx, keyA, "value from ctxA")
+
+	keyC := keyA // same key with keyA
+	ctxC := context.WithValue(ctx, keyC, "value from ctxC")
+
+	ctxB = context.WithValue(ctxA, "keyB", "value from ctxB")
+	ctxD = context.WithValue(ctxC, "keyD", "value from ctxD")
+	return ctxB, ctxD
+}
+
+func TestDepth(t *testing.T) {
+	ctxB, ctxD := mainChain()
+	if got := Depth(context.Background()); got != 0 {
+		t.Errorf("Depth(Background) = %d, want 0", got)
+	}
+	if got := Depth(ctxB); got != 2 {
+		t.Errorf("Depth(ctxB) = %d, want 2", got)
+	}
+	if got := Depth(ctxD); got != 2 {
+		t.Errorf("Depth(ctxD) = %d, want 2", got)
+	}
+}
+
+func TestSource(t *testing.T) {
+	ctxB, ctxD := mainChain()
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		key   any
+		depth int
+		found bool
+	}{
+		{"ctxB keyA", ctxB, "keyA", 1, true},
+		{"ctxB keyB", ctxB, "keyB", 0, true},
+		{"ctxD keyA", ctxD, "keyA", 1, true},
+		{"ctxD keyD", ctxD, "keyD", 0, true},
+		{"ctxB keyD", ctxB, "keyD", -1, false},
+	}
+	for _, tt := range tests {
+		depth, found := Source(tt.ctx, tt.key)
+		if depth != tt.depth || found != tt.found {
+			t.Errorf("%s: Source = %d, %v, want %d, %v", tt.name, depth, found, tt.depth, tt.found)
+		}
+	}
+}
+
+func TestSourceOwnTypes(t *testing.T) {
+	ctxB, ctxD := mainChain()
+
+	lazy := WithLazy(ctxB, "keyL", func() any { return "lazy" })
+	if depth, found := Source(lazy, "keyL"); depth != 0 || !found {
+		t.Errorf("Source(WithLazy, keyL) = %d, %v, want 0, true", depth, found)
+	}
+	if depth, found := Source(lazy, "keyB"); depth != 1 || !found {
+		t.Errorf("Source(WithLazy, keyB) = %d, %v, want 1, true", depth, found)
+	}
+
+	merged, cancel := MergeContexts(ctxB, ctxD)
+	defer cancel()
+	if depth, found := Source(merged, "keyD"); depth != 1 || !found {
+		t.Errorf("Source(MergeContexts, keyD) = %d, %v, want 1, true", depth, found)
+	}
+
+	// keyA is in both parents; the first one wins, as it does for Value
+	kvs := Dump(merged)
+	for _, kv := range kvs {
+		if kv.Key == "keyA" {
+			if kv.Val != "value from ctxA" {
+				t.Errorf("first keyA in Dump = %v, want value from ctxA", kv.Val)
+			}
+			break
+		}
+	}
+	if got := StringValues(merged)["keyA"]; got != merged.Value("keyA") {
+		t.Errorf("StringValues keyA = %v, want %v", got, merged.Value("keyA"))
+	}
+}
+
+// BenchmarkValueDepth measures a lookup of a missing key, which walks the
+// whole chain, against the depth of the chain.
+func BenchmarkValueDepth(b *testing.B) {
+	for _, depth := range []int{1, 4, 16, 64} {
+		ctx := context.Background()
+		for i := 0; i < depth; i++ {
+			ctx = context.WithValue(ctx, fieldKey(i), i)
+		}
+		b.Run(fmt.Sprint(depth), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink = ctx.Value("missing")
+			}
+		})
+	}
+}
@@ -0,0 +1,38 @@
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// detachCtx keeps the values of the embedded Context but none of its
+// cancellation. Every method except Value is overridden.
+type detachCtx struct {
+	context.Context
+}
+
+// Detach returns a context that resolves values against ctx but is never
+// canceled and has no deadline, so it can be handed to work that outlives
+// ctx.
+//
+// The returned context is not registered as a child of ctx: canceling ctx
+// does not touch it, and no goroutine is started to watch ctx on its
+// behalf.
+func Detach(ctx context.Context) context.Context {
+	if ctx == nil {
+		panic("cannot create context from nil parent")
+	}
+	return detachCtx{ctx}
+}
+
+func (detachCtx) Deadline() (deadline time.Time, ok bool) {
+	return
+}
+
+func (detachCtx) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachCtx) Err() error {
+	return nil
+}
@@ -0,0 +1,45 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ToDOT renders the chain from ctx up to its root as a Graphviz digraph.
+// Each node is a box labeled with its context type, plus key=value for
+// value nodes, and edges point from child to parent. Types that are not
+// from the context package are labeled with their Go type.
+func ToDOT(ctx context.Context) (string, error) {
+	if ctx == nil {
+		return "", errors.New("ctxutil: nil context")
+	}
+
+	var b strings.Builder
+	b.WriteString("digraph context {\n")
+	b.WriteString("\tnode [shape=box];\n")
+	walk(ctx, func(depth int, l layer) bool {
+		label := l.kind
+		if label == "" {
+			label = fmt.Sprintf("%T", l.ctx)
+		}
+		if l.kind == "valueCtx" {
+			label += fmt.Sprintf("\n%v=%v", l.key, l.val)
+		}
+		fmt.Fprintf(&b, "\tn%d [label=%s];\n", depth, dotQuote(label))
+		if depth > 0 {
+			fmt.Fprintf(&b, "\tn%d -> n%d;\n", depth-1, depth)
+		}
+		return true
+	})
+	b.WriteString("}\n")
+	return b.String(), nil
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
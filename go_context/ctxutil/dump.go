@@ -0,0 +1,65 @@
+package ctxutil
+
+import (
+	"context"
+	"iter"
+)
+
+// KeyValue is a key/value pair found in a context chain. Depth is the
+// number of hops from the context passed to Dump, 0 being the leaf.
+type KeyValue struct {
+	Key   any
+	Val   any
+	Depth int
+}
+
+// Dump walks from ctx up to the root and returns every key/value pair
+// stored along the way, leaf first, in the order Value would look them up.
+// Nodes that carry no value, such as cancel or timeout contexts, are
+// skipped. Lazy values are computed.
+func Dump(ctx context.Context) []KeyValue {
+	var kvs []KeyValue
+	lookup(ctx, func(depth int, l layer) bool {
+		return l.pairs(func(p pair) bool {
+			kvs = append(kvs, KeyValue{Key: p.key, Val: p.value(), Depth: depth})
+			return true
+		})
+	})
+	return kvs
+}
+
+// Values returns an iterator over the key/value pairs stored along the
+// chain of ctx, leaf first, like Dump. Shadowed pairs are yielded too.
+// Breaking out of the loop stops the walk.
+//
+//	for k, v := range ctxutil.Values(ctx) {
+//		fmt.Println(k, v)
+//	}
+func Values(ctx context.Context) iter.Seq2[any, any] {
+	return func(yield func(any, any) bool) {
+		lookup(ctx, func(_ int, l layer) bool {
+			return l.pairs(func(p pair) bool {
+				return yield(p.key, p.value())
+			})
+		})
+	}
+}
+
+// StringValues returns the values stored along the chain of ctx under keys
+// of type string, by key. For a key stored more than once the value nearest
+// to ctx wins, as it would for Value. Keys of other types, including named
+// string types, are left out.
+func StringValues(ctx context.Context) map[string]any {
+	m := make(map[string]any)
+	lookup(ctx, func(_ int, l layer) bool {
+		return l.pairs(func(p pair) bool {
+			if k, ok := p.key.(string); ok {
+				if _, shadowed := m[k]; !shadowed {
+					m[k] = p.value()
+				}
+			}
+			return true
+		})
+	})
+	return m
+}
@@ -0,0 +1,75 @@
+package ctxutil
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValues(t *testing.T) {
+	ctxB, _ := mainChain()
+
+	var got []KeyValue
+	for k, v := range Values(ctxB) {
+		got = append(got, KeyValue{Key: k, Val: v})
+	}
+	want := []KeyValue{
+		{Key: "keyB", Val: "value from ctxB"},
+		{Key: "keyA", Val: "value from ctxA"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Values = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("pair %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestValuesBreak(t *testing.T) {
+	ctxB, _ := mainChain()
+
+	// reaching the lazy node would compute its value
+	computed := false
+	ctx := WithLazy(context.Background(), "keyL", func() any {
+		computed = true
+		return "lazy"
+	})
+	ctx = context.WithValue(ctx, "keyE", "value from ctxE")
+	merged, cancel := MergeContexts(ctxB, ctx)
+	defer cancel()
+
+	n := 0
+	for k := range Values(merged) {
+		n++
+		if k != "keyB" {
+			t.Errorf("first key = %v, want keyB", k)
+		}
+		break
+	}
+	if n != 1 {
+		t.Errorf("loop ran %d times, want 1", n)
+	}
+	if computed {
+		t.Error("the walk went on after break")
+	}
+}
+
+func TestStringValuesShadowed(t *testing.T) {
+	ctxB, _ := mainChain()
+	ctx := context.WithValue(ctxB, "keyA", "value from leaf")
+	ctx = context.WithValue(ctx, namedKey("keyB"), "named")
+
+	got := StringValues(ctx)
+	want := map[string]any{"keyA": "value from leaf", "keyB": "value from ctxB"}
+	if len(got) != len(want) {
+		t.Fatalf("StringValues = %v, want %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("StringValues[%s] = %v, want %v", k, got[k], v)
+		}
+	}
+}
+
+type namedKey string
@@ -0,0 +1,32 @@
+package ctxutil
+
+import (
+	"context"
+	"reflect"
+)
+
+// ValuesEqual reports whether a and b resolve the same value, under ==,
+// for every key in keys. Comparing the contexts themselves is rarely
+// useful, as every WithValue returns a new one.
+//
+// Values that can not be compared with ==, such as slices, are compared
+// with reflect.DeepEqual instead.
+func ValuesEqual(a, b context.Context, keys []any) bool {
+	for _, key := range keys {
+		if !valueEqual(a.Value(key), b.Value(key)) {
+			return false
+		}
+	}
+	return true
+}
+
+func valueEqual(x, y any) (eq bool) {
+	defer func() {
+		// == panics on uncomparable dynamic types, even nested ones like a
+		// slice inside a struct field of interface type
+		if recover() != nil {
+			eq = reflect.DeepEqual(x, y)
+		}
+	}()
+	return x == y
+}
@@ -0,0 +1,35 @@
+package ctxutil
+
+import (
+	"context"
+	"testing"
+)
+
+func TestValuesEqual(t *testing.T) {
+	ctxB, ctxD := mainChain()
+	keyA := []any{"keyA"}
+
+	rebuilt := context.WithValue(context.Background(), "keyA", "value from ctxA")
+	if !ValuesEqual(ctxB, rebuilt, keyA) {
+		t.Error("ctxB and a rebuilt chain differ on keyA")
+	}
+	if ValuesEqual(ctxB, ctxD, keyA) {
+		t.Error("ctxB and ctxD agree on keyA")
+	}
+	if ValuesEqual(ctxB, rebuilt, []any{"keyA", "keyB"}) {
+		t.Error("ctxB and the rebuilt chain agree on keyB")
+	}
+}
+
+func TestValuesEqualDeepEqual(t *testing.T) {
+	a := context.WithValue(context.Background(), "keyA", []string{"x", "y"})
+	b := context.WithValue(context.Background(), "keyA", []string{"x", "y"})
+	c := context.WithValue(context.Background(), "keyA", []string{"x"})
+
+	if !ValuesEqual(a, b, []any{"keyA"}) {
+		t.Error("equal slices compare unequal")
+	}
+	if ValuesEqual(a, c, []any{"keyA"}) {
+		t.Error("different slices compare equal")
+	}
+}
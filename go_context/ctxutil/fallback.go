@@ -0,0 +1,28 @@
+package ctxutil
+
+import "context"
+
+// fallbackCtx is the embedded primary context, with values missing from it
+// looked up in fallback.
+type fallbackCtx struct {
+	context.Context
+	fallback context.Context
+}
+
+// WithFallback returns a context that looks values up in primary first and
+// then, only when primary returns nil, in fallback. Deadline, Done and Err
+// all come from primary alone; fallback's deadline and cancellation are
+// ignored.
+func WithFallback(primary, fallback context.Context) context.Context {
+	if primary == nil || fallback == nil {
+		panic("cannot create context from nil parent")
+	}
+	return &fallbackCtx{Context: primary, fallback: fallback}
+}
+
+func (c *fallbackCtx) Value(key any) any {
+	if v := c.Context.Value(key); v != nil {
+		return v
+	}
+	return c.fallback.Value(key)
+}
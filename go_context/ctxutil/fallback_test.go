@@ -0,0 +1,49 @@
+package ctxutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWithFallback(t *testing.T) {
+	primary := context.WithValue(context.Background(), "keyP", "value from primary")
+	primary = context.WithValue(primary, "keyA", "value from primary")
+	fallback := context.WithValue(context.Background(), "keyF", "value from fallback")
+	fallback = context.WithValue(fallback, "keyA", "value from fallback")
+	ctx := WithFallback(primary, fallback)
+
+	tests := []struct {
+		key   string
+		want  string
+		depth int
+	}{
+		{"keyF", "value from fallback", 2},
+		{"keyP", "value from primary", 2},
+		{"keyA", "value from primary", 1}, // primary wins
+	}
+	for _, tt := range tests {
+		if got := ctx.Value(tt.key); got != tt.want {
+			t.Errorf("Value(%s) = %v, want %s", tt.key, got, tt.want)
+		}
+		if got := StringValues(ctx)[tt.key]; got != tt.want {
+			t.Errorf("StringValues[%s] = %v, want %s", tt.key, got, tt.want)
+		}
+		if depth, found := Source(ctx, tt.key); depth != tt.depth || !found {
+			t.Errorf("Source(%s) = %d, %v, want %d, true", tt.key, depth, found, tt.depth)
+		}
+	}
+}
+
+func TestWithFallbackIgnoresCancellation(t *testing.T) {
+	fallback, cancel := context.WithTimeout(context.Background(), time.Hour)
+	cancel()
+	ctx := WithFallback(context.Background(), fallback)
+
+	if _, ok := ctx.Deadline(); ok {
+		t.Error("fallback's deadline is inherited")
+	}
+	if ctx.Done() != nil || ctx.Err() != nil {
+		t.Errorf("fallback's cancellation is inherited, Err() = %v", ctx.Err())
+	}
+}
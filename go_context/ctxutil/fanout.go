@@ -0,0 +1,30 @@
+package ctxutil
+
+import "context"
+
+type workerIDKey struct{}
+
+// FanOut returns n children of parent, one per worker, each carrying its
+// index as a WorkerID.
+//
+// The children are plain value nodes, so they share parent's Done channel:
+// canceling parent cancels all of them, and nothing is registered with the
+// parent or left running that would need a CancelFunc to release.
+//
+// For n <= 0 it returns nil.
+func FanOut(parent context.Context, n int) []context.Context {
+	if n <= 0 {
+		return nil
+	}
+	ctxs := make([]context.Context, n)
+	for i := range ctxs {
+		ctxs[i] = context.WithValue(parent, workerIDKey{}, i)
+	}
+	return ctxs
+}
+
+// WorkerID returns the worker index attached by FanOut.
+func WorkerID(ctx context.Context) (int, bool) {
+	id, ok := ctx.Value(workerIDKey{}).(int)
+	return id, ok
+}
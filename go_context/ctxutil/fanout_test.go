@@ -0,0 +1,43 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestFanOut(t *testing.T) {
+	parent, cancel := context.WithCancel(context.Background())
+	ctxs := FanOut(parent, 4)
+	if len(ctxs) != 4 {
+		t.Fatalf("FanOut returned %d contexts, want 4", len(ctxs))
+	}
+	for i, ctx := range ctxs {
+		if id, ok := WorkerID(ctx); id != i || !ok {
+			t.Errorf("WorkerID(ctxs[%d]) = %d, %v, want %d, true", i, id, ok, i)
+		}
+	}
+	if _, ok := WorkerID(parent); ok {
+		t.Error("parent has a WorkerID")
+	}
+
+	cancel()
+	for i, ctx := range ctxs {
+		select {
+		case <-ctx.Done():
+		default:
+			t.Errorf("ctxs[%d] not done after the parent was canceled", i)
+		}
+		if !errors.Is(ctx.Err(), context.Canceled) {
+			t.Errorf("ctxs[%d].Err() = %v, want context.Canceled", i, ctx.Err())
+		}
+	}
+}
+
+func TestFanOutNone(t *testing.T) {
+	for _, n := range []int{0, -1} {
+		if ctxs := FanOut(context.Background(), n); ctxs != nil {
+			t.Errorf("FanOut(%d) = %v, want nil", n, ctxs)
+		}
+	}
+}
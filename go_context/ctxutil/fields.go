@@ -0,0 +1,70 @@
+package ctxutil
+
+import (
+	"context"
+	"reflect"
+)
+
+// MaxFields is how many pairs a Fields can hold.
+const MaxFields = 8
+
+// Fields is a fixed size set of up to MaxFields key/value pairs. The zero
+// value is empty and ready to use.
+type Fields struct {
+	n    int
+	keys [MaxFields]any
+	vals [MaxFields]any
+}
+
+// Set stores val under key, replacing an earlier value of the same key. It
+// panics on a nil or uncomparable key, like context.WithValue, and when a
+// new key does not fit.
+func (f *Fields) Set(key, val any) {
+	if key == nil {
+		panic("nil key")
+	}
+	if !reflect.TypeOf(key).Comparable() {
+		panic("key is not comparable")
+	}
+	for i := 0; i < f.n; i++ {
+		if f.keys[i] == key {
+			f.vals[i] = val
+			return
+		}
+	}
+	if f.n == MaxFields {
+		panic("ctxutil: too many fields")
+	}
+	f.keys[f.n], f.vals[f.n] = key, val
+	f.n++
+}
+
+// Len returns the number of pairs in f.
+func (f *Fields) Len() int {
+	return f.n
+}
+
+// fieldsCtx holds several pairs in one node.
+type fieldsCtx struct {
+	context.Context
+	fields Fields
+}
+
+// WithFields returns a child of parent holding every pair of fields. Unlike
+// one WithValue per pair, it makes a single allocation, and a lookup scans
+// the pairs in place before moving on to parent. fields is copied.
+func WithFields(parent context.Context, fields Fields) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	return &fieldsCtx{Context: parent, fields: fields}
+}
+
+func (c *fieldsCtx) Value(key any) any {
+	for i := 0; i < c.fields.n; i++ {
+		if c.fields.keys[i] == key {
+			return c.fields.vals[i]
+		}
+	}
+	return c.Context.Value(key)
+}
@@ -0,0 +1,112 @@
+package ctxutil
+
+import (
+	"context"
+	"testing"
+)
+
+type fieldKey int
+
+// sink keeps benchmark results alive.
+var sink any
+
+func eightFields() Fields {
+	var f Fields
+	for i := 0; i < MaxFields; i++ {
+		f.Set(fieldKey(i), i)
+	}
+	return f
+}
+
+func eightValues(parent context.Context) context.Context {
+	ctx := parent
+	for i := 0; i < MaxFields; i++ {
+		ctx = context.WithValue(ctx, fieldKey(i), i)
+	}
+	return ctx
+}
+
+func TestFieldsWalk(t *testing.T) {
+	var f Fields
+	f.Set("x", 1)
+	f.Set("y", 2)
+	ctx := WithFields(context.WithValue(context.Background(), "x", 0), f)
+
+	if depth, found := Source(ctx, "x"); depth != 0 || !found {
+		t.Errorf("Source(x) = %d, %v, want 0, true", depth, found)
+	}
+	if got := StringValues(ctx); got["x"] != 1 || got["y"] != 2 {
+		t.Errorf("StringValues = %v, want x=1 y=2", got)
+	}
+
+	want := []KeyValue{{"x", 1, 0}, {"y", 2, 0}, {"x", 0, 1}}
+	got := Dump(ctx)
+	if len(got) != len(want) {
+		t.Fatalf("Dump = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Dump[%d] = %v, want %v", i, got[i], want[i])
+		}
+	}
+
+	n := 0
+	for range Values(ctx) {
+		n++
+	}
+	if n != len(want) {
+		t.Errorf("Values yielded %d pairs, want %d", n, len(want))
+	}
+}
+
+// lookupPositions are where the lookup benchmarks find their key: after
+// one compare, in the middle, after all MaxFields compares, or not at all.
+// Fields scans its pairs in the order they were set, and a WithValue chain
+// from the last pair set, so key maps a position to the key found there.
+var lookupPositions = []struct {
+	name string
+	pos  int // -1 for a missing key
+}{
+	{"nearest", 0},
+	{"middle", MaxFields / 2},
+	{"farthest", MaxFields - 1},
+	{"missing", -1},
+}
+
+func benchmarkLookup(b *testing.B, c context.Context, key func(pos int) any) {
+	for _, p := range lookupPositions {
+		var k any = "missing"
+		if p.pos >= 0 {
+			k = key(p.pos)
+		}
+		b.Run("lookup/"+p.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink = c.Value(k)
+			}
+		})
+	}
+}
+
+func BenchmarkWithFields(b *testing.B) {
+	ctx := context.Background()
+	b.Run("construct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sink = WithFields(ctx, eightFields())
+		}
+	})
+	benchmarkLookup(b, WithFields(ctx, eightFields()), func(pos int) any {
+		return fieldKey(pos)
+	})
+}
+
+func BenchmarkWithValue8(b *testing.B) {
+	ctx := context.Background()
+	b.Run("construct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sink = eightValues(ctx)
+		}
+	})
+	benchmarkLookup(b, eightValues(ctx), func(pos int) any {
+		return fieldKey(MaxFields - 1 - pos)
+	})
+}
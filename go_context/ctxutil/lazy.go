@@ -0,0 +1,45 @@
+package ctxutil
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// lazyCtx is a valueCtx whose value is computed on first use.
+type lazyCtx struct {
+	context.Context
+	key any
+
+	once    sync.Once
+	compute func() any
+	val     any
+}
+
+// WithLazy returns a child of parent whose value for key is the result of
+// compute. compute runs at most once, on the first Value(key) call, even
+// when several goroutines look the key up at the same time; later lookups
+// return the cached result.
+func WithLazy(parent context.Context, key any, compute func() any) context.Context {
+	if parent == nil {
+		panic("cannot create context from nil parent")
+	}
+	if key == nil {
+		panic("nil key")
+	}
+	if !reflect.TypeOf(key).Comparable() {
+		panic("key is not comparable")
+	}
+	return &lazyCtx{Context: parent, key: key, compute: compute}
+}
+
+func (c *lazyCtx) Value(key any) any {
+	if c.key != key {
+		return c.Context.Value(key)
+	}
+	c.once.Do(func() {
+		c.val = c.compute()
+		c.compute = nil // let the closure be collected
+	})
+	return c.val
+}
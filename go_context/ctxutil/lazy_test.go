@@ -0,0 +1,36 @@
+package ctxutil
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestLazyOnce is meant to be run with -race.
+func TestLazyOnce(t *testing.T) {
+	var calls atomic.Int32
+	ctx := WithLazy(context.Background(), "keyA", func() any {
+		calls.Add(1)
+		return "value from compute"
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if got := ctx.Value("keyA"); got != "value from compute" {
+				t.Errorf("Value(keyA) = %v", got)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("compute ran %d times, want 1", n)
+	}
+	if got := ctx.Value("keyB"); got != nil {
+		t.Errorf("Value(keyB) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,34 @@
+package ctxutil
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalValues looks up each of keys in ctx and encodes the values found
+// as a JSON object, keyed by the fmt form of each key. Keys that are not in
+// the list are never looked at, so values stored under other keys can not
+// leak. Keys without a value are left out.
+//
+// If a value can not be encoded, MarshalValues returns an error naming its
+// key and no output.
+func MarshalValues(ctx context.Context, keys []any) ([]byte, error) {
+	obj := make(map[string]json.RawMessage, len(keys))
+	for _, key := range keys {
+		val := ctx.Value(key)
+		if val == nil {
+			continue
+		}
+		name := fmt.Sprint(key)
+		if _, dup := obj[name]; dup {
+			return nil, fmt.Errorf("ctxutil: more than one key is named %q", name)
+		}
+		raw, err := json.Marshal(val)
+		if err != nil {
+			return nil, fmt.Errorf("ctxutil: marshaling value of key %s: %w", formatKey(key), err)
+		}
+		obj[name] = raw
+	}
+	return json.Marshal(obj)
+}
@@ -0,0 +1,83 @@
+package ctxutil
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// mergeCtx is done as soon as any of its parents is done.
+type mergeCtx struct {
+	parents []context.Context
+	done    chan struct{}
+
+	mu  sync.Mutex
+	err error // set by the first cancel call
+}
+
+// MergeContexts returns a context that is done when the first of parents is
+// done, reporting that parent's error. Values are looked up in each parent
+// in turn, and the earliest parent deadline is used.
+//
+// Calling the returned CancelFunc stops watching the parents; if no parent
+// has fired yet, Err reports context.Canceled.
+func MergeContexts(parents ...context.Context) (context.Context, context.CancelFunc) {
+	c := &mergeCtx{parents: parents, done: make(chan struct{})}
+
+	// case 0 is the merged context itself, so cancel stops the watcher
+	cases := []reflect.SelectCase{{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.done)}}
+	watched := []context.Context{c}
+	for _, p := range parents {
+		if d := p.Done(); d != nil {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(d)})
+			watched = append(watched, p)
+		}
+	}
+	if len(cases) > 1 {
+		go func() {
+			if i, _, _ := reflect.Select(cases); i > 0 {
+				c.cancel(watched[i].Err())
+			}
+		}()
+	}
+	return c, func() { c.cancel(context.Canceled) }
+}
+
+func (c *mergeCtx) cancel(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return // already canceled
+	}
+	c.err = err
+	close(c.done)
+}
+
+func (c *mergeCtx) Deadline() (deadline time.Time, ok bool) {
+	for _, p := range c.parents {
+		if d, has := p.Deadline(); has && (!ok || d.Before(deadline)) {
+			deadline, ok = d, true
+		}
+	}
+	return
+}
+
+func (c *mergeCtx) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *mergeCtx) Err() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+func (c *mergeCtx) Value(key any) any {
+	for _, p := range c.parents {
+		if v := p.Value(key); v != nil {
+			return v
+		}
+	}
+	return nil
+}
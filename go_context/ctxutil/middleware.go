@@ -0,0 +1,22 @@
+package ctxutil
+
+import "context"
+
+// Handler handles a request described by ctx.
+type Handler func(ctx context.Context) error
+
+// Middleware wraps a Handler. It may derive a new context, for example to
+// attach a value, before calling next with it.
+type Middleware func(next Handler) Handler
+
+// Chain composes mws left to right: the first middleware is the outermost
+// and sees the request first. Chain() returns a middleware that leaves the
+// handler unchanged.
+func Chain(mws ...Middleware) Middleware {
+	return func(next Handler) Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			next = mws[i](next)
+		}
+		return next
+	}
+}
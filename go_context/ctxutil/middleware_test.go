@@ -0,0 +1,52 @@
+package ctxutil
+
+import (
+	"context"
+	"testing"
+)
+
+// withKey returns a middleware that attaches key before calling next.
+func withKey(key string, order *[]string) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context) error {
+			*order = append(*order, key)
+			return next(context.WithValue(ctx, key, "value from "+key))
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	var order []string
+	mw := Chain(withKey("keyA", &order), withKey("keyB", &order), withKey("keyC", &order))
+
+	var seen map[string]any
+	h := mw(func(ctx context.Context) error {
+		seen = StringValues(ctx)
+		return nil
+	})
+	if err := h(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, key := range []string{"keyA", "keyB", "keyC"} {
+		if seen[key] != "value from "+key {
+			t.Errorf("handler saw %s = %v", key, seen[key])
+		}
+	}
+	if len(order) != 3 || order[0] != "keyA" || order[2] != "keyC" {
+		t.Errorf("middlewares ran in order %v, want keyA keyB keyC", order)
+	}
+}
+
+func TestChainIdentity(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "keyA", "value from ctxA")
+	var got context.Context
+	h := Chain()(func(ctx context.Context) error {
+		got = ctx
+		return nil
+	})
+	h(ctx)
+	if got != ctx {
+		t.Error("Chain() changed the context")
+	}
+}
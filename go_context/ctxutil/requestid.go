@@ -0,0 +1,43 @@
+package ctxutil
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// requestIDKey is unexported, so no other package can build a key equal
+// to it.
+type requestIDKey struct{}
+
+// WithRequestID returns a child of ctx carrying id as its request ID.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, if any.
+func RequestID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok
+}
+
+// EnsureRequestID returns ctx and its request ID if it already has one.
+// Otherwise it generates a random ID and returns a child carrying it.
+func EnsureRequestID(ctx context.Context) (context.Context, string) {
+	if id, ok := RequestID(ctx); ok {
+		return ctx, id
+	}
+	id := newRequestID()
+	return WithRequestID(ctx, id), id
+}
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("ctxutil: generating request ID: %v", err))
+	}
+	b[6] = b[6]&0x0f | 0x40 // version 4
+	b[8] = b[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
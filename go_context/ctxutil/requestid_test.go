@@ -0,0 +1,31 @@
+package ctxutil
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestRequestIDChild(t *testing.T) {
+	ctx := WithRequestID(context.Background(), "req-1")
+	child, cancel := context.WithTimeout(context.WithValue(ctx, "keyA", "value from ctxA"), time.Hour)
+	defer cancel()
+
+	if id, ok := RequestID(child); id != "req-1" || !ok {
+		t.Errorf("RequestID(child) = %q, %v, want req-1, true", id, ok)
+	}
+	if _, ok := RequestID(context.Background()); ok {
+		t.Error("Background has a request ID")
+	}
+}
+
+func TestEnsureRequestID(t *testing.T) {
+	ctx, id := EnsureRequestID(context.Background())
+	if len(id) != 36 {
+		t.Errorf("generated ID %q is not a UUID", id)
+	}
+	child := context.WithValue(ctx, "keyA", "value from ctxA")
+	if same, got := EnsureRequestID(child); same != child || got != id {
+		t.Errorf("EnsureRequestID(child) = %q, want the existing %q", got, id)
+	}
+}
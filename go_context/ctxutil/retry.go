@@ -0,0 +1,87 @@
+package ctxutil
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+type retryConfig struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	jitter      float64
+}
+
+// RetryOption configures Retry.
+type RetryOption func(*retryConfig)
+
+// WithMaxAttempts sets how many times fn is called at most, 5 by default.
+// n <= 0 retries until fn succeeds or the context is done.
+func WithMaxAttempts(n int) RetryOption {
+	return func(c *retryConfig) { c.maxAttempts = n }
+}
+
+// WithBaseDelay sets the delay before the first retry, 100ms by default.
+func WithBaseDelay(d time.Duration) RetryOption {
+	return func(c *retryConfig) { c.baseDelay = d }
+}
+
+// WithMaxDelay caps the delay between attempts, 10s by default.
+func WithMaxDelay(d time.Duration) RetryOption {
+	return func(c *retryConfig) { c.maxDelay = d }
+}
+
+// WithJitter adds a random extra delay of up to frac times the current
+// delay, so that many clients do not retry in lockstep.
+func WithJitter(frac float64) RetryOption {
+	return func(c *retryConfig) { c.jitter = frac }
+}
+
+// Retry calls fn until it returns nil, the attempts are used up, or ctx is
+// done. The delay between attempts starts at the base delay and doubles
+// each time. No delay, jitter included, is longer than the max delay.
+//
+// If ctx is done, Retry returns ctx.Err() at once, even in the middle of a
+// delay. Otherwise it returns the error of the last attempt.
+func Retry(ctx context.Context, fn func(ctx context.Context) error, opts ...RetryOption) error {
+	cfg := retryConfig{
+		maxAttempts: 5,
+		baseDelay:   100 * time.Millisecond,
+		maxDelay:    10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	delay := min(cfg.baseDelay, cfg.maxDelay)
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := fn(ctx)
+		if err == nil {
+			return nil
+		}
+		if cfg.maxAttempts > 0 && attempt >= cfg.maxAttempts {
+			return err
+		}
+
+		wait := delay
+		if cfg.jitter > 0 {
+			wait += time.Duration(rand.Float64() * cfg.jitter * float64(delay))
+			wait = min(wait, cfg.maxDelay)
+		}
+		t := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+
+		if delay *= 2; delay > cfg.maxDelay {
+			delay = cfg.maxDelay
+		}
+	}
+}
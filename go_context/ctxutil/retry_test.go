@@ -0,0 +1,54 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRetryCanceledDuringDelay(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	errFail := errors.New("fail")
+
+	attempts := 0
+	fn := func(ctx context.Context) error {
+		attempts++
+		time.AfterFunc(10*time.Millisecond, cancel) // cancel during the delay
+		return errFail
+	}
+
+	start := time.Now()
+	err := Retry(ctx, fn, WithBaseDelay(time.Hour))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Retry took %v after cancel", elapsed)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Retry = %v, want context.Canceled", err)
+	}
+	if attempts != 1 {
+		t.Errorf("fn called %d times, want 1", attempts)
+	}
+}
+
+func TestRetryAttempts(t *testing.T) {
+	errFail := errors.New("fail")
+	attempts := 0
+	err := Retry(context.Background(), func(context.Context) error {
+		attempts++
+		return errFail
+	}, WithMaxAttempts(3), WithBaseDelay(time.Millisecond))
+	if err != errFail || attempts != 3 {
+		t.Errorf("Retry = %v after %d attempts, want %v after 3", err, attempts, errFail)
+	}
+}
+
+func TestRetryMaxDelay(t *testing.T) {
+	errFail := errors.New("fail")
+	start := time.Now()
+	Retry(context.Background(), func(context.Context) error { return errFail },
+		WithMaxAttempts(3), WithBaseDelay(time.Hour), WithMaxDelay(10*time.Millisecond), WithJitter(1))
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("2 delays capped at 10ms took %v", elapsed)
+	}
+}
@@ -0,0 +1,39 @@
+package ctxutil
+
+import "context"
+
+// Semaphore limits how many holders may run at once.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore returns a semaphore with n slots.
+func NewSemaphore(n int) *Semaphore {
+	if n <= 0 {
+		panic("ctxutil: semaphore needs at least one slot")
+	}
+	return &Semaphore{slots: make(chan struct{}, n)}
+}
+
+// Acquire takes a slot, blocking until one is free or ctx is done. In the
+// latter case it returns ctx.Err() and no slot is taken.
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release gives back a slot taken by Acquire.
+func (s *Semaphore) Release() {
+	select {
+	case <-s.slots:
+	default:
+		panic("ctxutil: semaphore released more than acquired")
+	}
+}
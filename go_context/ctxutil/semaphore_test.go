@@ -0,0 +1,30 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSemaphore(t *testing.T) {
+	s := NewSemaphore(2)
+	for i := 0; i < 2; i++ {
+		if err := s.Acquire(context.Background()); err != nil {
+			t.Fatalf("Acquire #%d = %v", i, err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := s.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Acquire on a full semaphore = %v, want context.DeadlineExceeded", err)
+	}
+
+	s.Release()
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := s.Acquire(ctx); err != nil {
+		t.Errorf("Acquire after Release = %v, want nil", err)
+	}
+}
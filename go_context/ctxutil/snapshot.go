@@ -0,0 +1,33 @@
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// Snapshot copies the values of keys out of ctx into a plain map, so they
+// can cross a boundary the context itself can not, such as a worker pool
+// queue. Keys without a value are left out.
+func Snapshot(ctx context.Context, keys []any) map[any]any {
+	snap := make(map[any]any, len(keys))
+	for _, key := range keys {
+		if val := ctx.Value(key); val != nil {
+			snap[key] = val
+		}
+	}
+	return snap
+}
+
+// Restore attaches the values captured by Snapshot to parent. The result
+// does not depend on the context the snapshot was taken from.
+func Restore(parent context.Context, snap map[any]any) context.Context {
+	return WithValues(parent, snap)
+}
+
+// DeriveWithTimeout returns a fresh context.WithTimeout(context.Background(), d)
+// carrying the values of keys copied from src. It shares nothing else with
+// src: canceling src, or src's deadline passing, does not affect it. Keys
+// without a value in src are skipped.
+func DeriveWithTimeout(src context.Context, d time.Duration, keys []any) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(Restore(context.Background(), Snapshot(src, keys)), d)
+}
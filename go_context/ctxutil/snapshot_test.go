@@ -0,0 +1,59 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	ctxB, ctxD := mainChain()
+	keys := []any{"keyA", "keyB", "keyD"}
+
+	snap := Snapshot(ctxB, keys)
+	if len(snap) != 2 {
+		t.Errorf("Snapshot = %v, want keyA and keyB only", snap)
+	}
+
+	// restoring under ctxD shadows its keyA with the one from ctxB
+	ctx := Restore(ctxD, snap)
+	for key, want := range map[any]any{
+		"keyA": "value from ctxA",
+		"keyB": "value from ctxB",
+		"keyD": "value from ctxD",
+	} {
+		if got := ctx.Value(key); got != want {
+			t.Errorf("Value(%v) = %v, want %v", key, got, want)
+		}
+	}
+
+	if got := Restore(context.Background(), snap); !ValuesEqual(got, ctxB, keys) {
+		t.Error("Restore(Background, Snapshot(ctxB)) resolves other values than ctxB")
+	}
+}
+
+func TestDeriveWithTimeout(t *testing.T) {
+	ctxB, _ := mainChain()
+	src, cancelSrc := context.WithCancel(ctxB)
+
+	ctx, cancel := DeriveWithTimeout(src, 20*time.Millisecond, []any{"keyA", "keyB"})
+	defer cancel()
+	cancelSrc()
+
+	if !ValuesEqual(ctx, ctxB, []any{"keyA", "keyB"}) {
+		t.Error("copied keys resolve other values than in src")
+	}
+	if ctx.Err() != nil {
+		t.Fatalf("Err() = %v after src was canceled, want nil", ctx.Err())
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("derived ctx did not expire")
+	}
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+}
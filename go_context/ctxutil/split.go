@@ -0,0 +1,41 @@
+package ctxutil
+
+import (
+	"context"
+	"time"
+)
+
+// SplitDeadline divides the time left until ctx's deadline into parts equal
+// slices for operations run one after another: the i-th child expires at
+// the end of the i-th slice, and the last one together with ctx.
+//
+// If ctx has no deadline, the children only inherit its cancellation. If
+// the deadline has already passed, the children are done at once. For
+// parts <= 0 both slices are nil.
+func SplitDeadline(ctx context.Context, parts int) ([]context.Context, []context.CancelFunc) {
+	if parts <= 0 {
+		return nil, nil
+	}
+
+	ctxs := make([]context.Context, parts)
+	cancels := make([]context.CancelFunc, parts)
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		for i := range ctxs {
+			ctxs[i], cancels[i] = context.WithCancel(ctx)
+		}
+		return ctxs, cancels
+	}
+
+	start := time.Now()
+	slice := deadline.Sub(start) / time.Duration(parts)
+	for i := range ctxs {
+		d := start.Add(slice * time.Duration(i+1))
+		if i == parts-1 {
+			d = deadline // don't lose the remainder of the division
+		}
+		ctxs[i], cancels[i] = context.WithDeadline(ctx, d)
+	}
+	return ctxs, cancels
+}
@@ -0,0 +1,56 @@
+package ctxutil
+
+import (
+	"context"
+	"sync"
+)
+
+type storeKey struct{}
+
+// Store is a mutable key/value map carried by a context. It is safe for
+// concurrent use.
+//
+// Values attached with context.WithValue are immutable: changing one means
+// deriving a new child, which only code holding that child will see. A
+// Store is shared by reference instead, so every goroutine holding a
+// context below WithStore sees every Set at once. The price is that the
+// usual guarantee is gone: a value read from the Store may change under
+// you, and a child can no longer shadow a value without affecting its
+// parent.
+type Store struct {
+	mu sync.RWMutex
+	m  map[any]any
+}
+
+// WithStore returns a child of parent carrying a new, empty Store.
+func WithStore(parent context.Context) context.Context {
+	return context.WithValue(parent, storeKey{}, &Store{m: make(map[any]any)})
+}
+
+// StoreFrom returns the nearest Store attached to ctx or its ancestors.
+func StoreFrom(ctx context.Context) (*Store, bool) {
+	s, ok := ctx.Value(storeKey{}).(*Store)
+	return s, ok
+}
+
+// Set stores val under key.
+func (s *Store) Set(key, val any) {
+	s.mu.Lock()
+	s.m[key] = val
+	s.mu.Unlock()
+}
+
+// Get returns the value stored under key.
+func (s *Store) Get(key any) (any, bool) {
+	s.mu.RLock()
+	val, ok := s.m[key]
+	s.mu.RUnlock()
+	return val, ok
+}
+
+// Delete removes key from the store.
+func (s *Store) Delete(key any) {
+	s.mu.Lock()
+	delete(s.m, key)
+	s.mu.Unlock()
+}
@@ -0,0 +1,41 @@
+package ctxutil
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+// TestStoreConcurrent is meant to be run with -race.
+func TestStoreConcurrent(t *testing.T) {
+	ctx := WithStore(context.Background())
+	child := context.WithValue(ctx, "keyA", "value from child")
+
+	const n = 16
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			s, _ := StoreFrom(ctx)
+			s.Set(i, i)
+		}()
+		go func() {
+			defer wg.Done()
+			s, _ := StoreFrom(child)
+			s.Get(i)
+		}()
+	}
+	wg.Wait()
+
+	// the child shares the parent's Store
+	s, ok := StoreFrom(child)
+	if !ok {
+		t.Fatal("no Store below WithStore")
+	}
+	for i := 0; i < n; i++ {
+		if v, ok := s.Get(i); !ok || v != i {
+			t.Errorf("Get(%d) = %v, %v, want %d, true", i, v, ok, i)
+		}
+	}
+}
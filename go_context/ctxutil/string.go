@@ -0,0 +1,65 @@
+package ctxutil
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// String formats the chain of ctx on one line, from the root down, like
+//
+//	Background.WithValue(keyA).WithValue(keyB).WithCancel
+//
+// Value nodes show only their key, never the value, so it is safe to log.
+// Timeout nodes show the time left. Nodes of unknown types are rendered as
+// <unknown>.
+func String(ctx context.Context) string {
+	var names []string
+	walk(ctx, func(_ int, l layer) bool {
+		names = append(names, layerName(l))
+		return true
+	})
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return strings.Join(names, ".")
+}
+
+func layerName(l layer) string {
+	switch l.kind {
+	case "emptyCtx":
+		if l.ctx == context.TODO() {
+			return "TODO"
+		}
+		return "Background"
+	case "valueCtx":
+		return fmt.Sprintf("WithValue(%v)", l.key)
+	case "cancelCtx":
+		return "WithCancel"
+	case "timerCtx":
+		d, _ := l.ctx.Deadline()
+		return fmt.Sprintf("WithTimeout(%v)", time.Until(d).Round(time.Millisecond))
+	case "withoutCancelCtx":
+		return "WithoutCancel"
+	case "afterFuncCtx":
+		return "AfterFunc"
+	}
+	switch ctx := l.ctx.(type) {
+	case detachCtx:
+		return "Detach"
+	case *mergeCtx:
+		return "MergeContexts"
+	case *lazyCtx:
+		return fmt.Sprintf("WithLazy(%v)", ctx.key)
+	case *fallbackCtx:
+		return "WithFallback"
+	case *fieldsCtx:
+		keys := make([]string, ctx.fields.n)
+		for i := range keys {
+			keys[i] = fmt.Sprint(ctx.fields.keys[i])
+		}
+		return fmt.Sprintf("WithFields(%s)", strings.Join(keys, ","))
+	}
+	return "<unknown>"
+}
@@ -0,0 +1,59 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInsufficientTime is returned by RequireTimeLeft when the deadline is
+// too close.
+var ErrInsufficientTime = errors.New("ctxutil: not enough time left before deadline")
+
+type timeLeftConfig struct {
+	clamp bool
+}
+
+// TimeLeftOption configures TimeLeft.
+type TimeLeftOption func(*timeLeftConfig)
+
+// ClampToZero makes TimeLeft report 0 instead of a negative duration once
+// the deadline has passed.
+func ClampToZero() TimeLeftOption {
+	return func(c *timeLeftConfig) { c.clamp = true }
+}
+
+// TimeLeft returns the time until ctx's deadline, and false if ctx has no
+// deadline. Once the deadline has passed the duration is not positive; it
+// is negative unless ClampToZero() is given.
+func TimeLeft(ctx context.Context, opts ...TimeLeftOption) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	var cfg timeLeftConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	left := time.Until(deadline)
+	if cfg.clamp && left < 0 {
+		left = 0
+	}
+	return left, true
+}
+
+// RequireTimeLeft returns an error wrapping ErrInsufficientTime if less
+// than min is left before ctx's deadline, so that costly work is not
+// started when it can not finish in time. A context without a deadline
+// always passes. If ctx is already done, its Err is returned.
+func RequireTimeLeft(ctx context.Context, min time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	left, ok := TimeLeft(ctx)
+	if ok && left < min {
+		return fmt.Errorf("%w: %v left, need %v", ErrInsufficientTime, left.Round(time.Millisecond), min)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTimeLeft(t *testing.T) {
+	if left, ok := TimeLeft(context.Background()); left != 0 || ok {
+		t.Errorf("TimeLeft(Background) = %v, %v, want 0, false", left, ok)
+	}
+
+	future, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if left, ok := TimeLeft(future); !ok || left <= 59*time.Minute || left > time.Hour {
+		t.Errorf("TimeLeft(1h) = %v, %v, want about 1h, true", left, ok)
+	}
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if left, ok := TimeLeft(expired); !ok || left >= 0 {
+		t.Errorf("TimeLeft(expired) = %v, %v, want negative, true", left, ok)
+	}
+	if left, ok := TimeLeft(expired, ClampToZero()); !ok || left != 0 {
+		t.Errorf("TimeLeft(expired, ClampToZero) = %v, %v, want 0, true", left, ok)
+	}
+}
+
+func TestRequireTimeLeft(t *testing.T) {
+	soon, cancelSoon := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancelSoon()
+	later, cancelLater := context.WithTimeout(context.Background(), time.Hour)
+	defer cancelLater()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"no deadline", context.Background(), nil},
+		{"enough time", later, nil},
+		{"too little time", soon, ErrInsufficientTime},
+		{"expired", expired, context.DeadlineExceeded},
+	}
+	for _, tt := range tests {
+		err := RequireTimeLeft(tt.ctx, time.Second)
+		if !errors.Is(err, tt.want) {
+			t.Errorf("%s: RequireTimeLeft = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package ctxutil collects small helpers built on top of the standard
+// context package.
+package ctxutil
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Get looks up key in ctx and asserts the value to T. It returns the zero
+// value and false if the key is missing or the value is not a T.
+func Get[T any](ctx context.Context, key any) (T, bool) {
+	v, ok := ctx.Value(key).(T)
+	return v, ok
+}
+
+// GetOr is like Get, but returns fallback instead of the zero value.
+func GetOr[T any](ctx context.Context, key any, fallback T) T {
+	if v, ok := Get[T](ctx, key); ok {
+		return v
+	}
+	return fallback
+}
+
+// MustValue is like Get, but panics if the key is missing or the value is
+// not a T.
+func MustValue[T any](ctx context.Context, key any) T {
+	raw := ctx.Value(key)
+	if v, ok := raw.(T); ok {
+		return v
+	}
+	want := reflect.TypeOf((*T)(nil)).Elem()
+	if raw == nil {
+		panic(fmt.Sprintf("context: key %s of type %s not found", formatKey(key), want))
+	}
+	panic(fmt.Sprintf("context: key %s has value of type %T, want %s", formatKey(key), raw, want))
+}
+
+// formatKey quotes string keys so they stand out in messages.
+func formatKey(key any) string {
+	if s, ok := key.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", key)
+}
+
+// FirstValue looks up key in each of ctxs in order and returns the first
+// non-nil value. nil contexts are skipped.
+func FirstValue(key any, ctxs ...context.Context) (any, bool) {
+	for _, ctx := range ctxs {
+		if ctx == nil {
+			continue
+		}
+		if v := ctx.Value(key); v != nil {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// FirstValueTyped is like FirstValue, but returns the first value that is
+// a T, skipping values of other types.
+func FirstValueTyped[T any](key any, ctxs ...context.Context) (T, bool) {
+	for _, ctx := range ctxs {
+		if ctx == nil {
+			continue
+		}
+		if v, ok := ctx.Value(key).(T); ok {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
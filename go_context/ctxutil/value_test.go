@@ -0,0 +1,35 @@
+package ctxutil
+
+import (
+	"context"
+	"testing"
+)
+
+// mustValuePanic returns what MustValue[int] panics with for key.
+func mustValuePanic(ctx context.Context, key any) (msg any) {
+	defer func() { msg = recover() }()
+	MustValue[int](ctx, key)
+	return nil
+}
+
+func TestMustValue(t *testing.T) {
+	ctx := context.WithValue(context.Background(), "keyA", "value from ctxA")
+	ctx = context.WithValue(ctx, "keyN", 42)
+
+	if got := MustValue[int](ctx, "keyN"); got != 42 {
+		t.Errorf("MustValue(keyN) = %d, want 42", got)
+	}
+
+	tests := []struct {
+		key  any
+		want string
+	}{
+		{"keyB", `context: key "keyB" of type int not found`},
+		{"keyA", `context: key "keyA" has value of type string, want int`},
+	}
+	for _, tt := range tests {
+		if got := mustValuePanic(ctx, tt.key); got != tt.want {
+			t.Errorf("MustValue(%v) panic = %v, want %s", tt.key, got, tt.want)
+		}
+	}
+}
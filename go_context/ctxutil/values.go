@@ -0,0 +1,56 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// WithValues attaches every pair of kv to parent, one valueCtx per pair, and
+// returns the last child.
+//
+// Map iteration order is not deterministic, so the order of the nodes in
+// the resulting chain is not either. That is harmless as long as keys are
+// distinct, and map keys are unique under ==, the same comparison Value
+// uses. A key that is not equal to itself, like a NaN float, could never be
+// looked up, so it panics.
+func WithValues(parent context.Context, kv map[any]any) context.Context {
+	ctx := parent
+	for k, v := range kv {
+		if k != k {
+			panic(fmt.Sprintf("ctxutil: key %#v is not equal to itself and could never be looked up", k))
+		}
+		ctx = context.WithValue(ctx, k, v)
+	}
+	return ctx
+}
+
+// SafeWithValue is like context.WithValue, but returns an error instead of
+// panicking when parent or key is nil, or when key is not comparable.
+func SafeWithValue(parent context.Context, key, val any) (context.Context, error) {
+	if parent == nil {
+		return nil, errors.New("context: nil parent")
+	}
+	if key == nil {
+		return nil, errors.New("context: nil key")
+	}
+	if t := reflect.TypeOf(key); !t.Comparable() {
+		return nil, fmt.Errorf("context: key of type %s is not comparable", t)
+	}
+	return context.WithValue(parent, key, val), nil
+}
+
+// WithValueIfAbsent attaches val under key only if parent has no value for
+// it yet, and returns parent unchanged otherwise, so an inherited value is
+// never shadowed.
+//
+// Value returns nil both for a missing key and for one stored with a nil
+// value, so a key explicitly set to nil counts as absent and is
+// overwritten.
+func WithValueIfAbsent(parent context.Context, key, val any) context.Context {
+	if parent.Value(key) != nil {
+		return parent
+	}
+	return context.WithValue(parent, key, val)
+}
@@ -0,0 +1,102 @@
+package ctxutil
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWithValuesNaNKey(t *testing.T) {
+	defer func() {
+		msg, _ := recover().(string)
+		if !strings.Contains(msg, "is not equal to itself") {
+			t.Errorf("recover() = %q, want a not equal to itself panic", msg)
+		}
+	}()
+	WithValues(context.Background(), map[any]any{math.NaN(): 1})
+}
+
+func TestWithValues(t *testing.T) {
+	ctx := WithValues(context.Background(), map[any]any{"keyA": 1, "keyB": 2})
+	if ctx.Value("keyA") != 1 || ctx.Value("keyB") != 2 {
+		t.Errorf("keyA = %v, keyB = %v, want 1, 2", ctx.Value("keyA"), ctx.Value("keyB"))
+	}
+}
+
+func BenchmarkWithValuesLookup(b *testing.B) {
+	kv := map[any]any{"keyA": 1, "keyB": 2, "keyC": 3, "keyD": 4}
+	chained := context.WithValue(context.Background(), "keyA", 1)
+	chained = context.WithValue(chained, "keyB", 2)
+	chained = context.WithValue(chained, "keyC", 3)
+	chained = context.WithValue(chained, "keyD", 4)
+
+	for _, c := range []struct {
+		name string
+		ctx  context.Context
+	}{
+		{"map", WithValues(context.Background(), kv)},
+		{"chained", chained},
+	} {
+		// keyA is the deepest node of chained, and at a random depth in the
+		// map-built chain
+		b.Run(c.name+"/hit", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink = c.ctx.Value("keyA")
+			}
+		})
+		b.Run(c.name+"/miss", func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sink = c.ctx.Value("missing")
+			}
+		})
+	}
+}
+
+func TestSafeWithValue(t *testing.T) {
+	tests := []struct {
+		name string
+		key  any
+		err  string
+	}{
+		{"nil key", nil, "context: nil key"},
+		{"slice key", []string{"keyA"}, "context: key of type []string is not comparable"},
+		{"valid key", "keyA", ""},
+	}
+	for _, tt := range tests {
+		ctx, err := SafeWithValue(context.Background(), tt.key, "value from ctxA")
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err || ctx != nil {
+				t.Errorf("%s: SafeWithValue = %v, %v, want nil, %s", tt.name, ctx, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: SafeWithValue = %v", tt.name, err)
+		}
+		if got := ctx.Value(tt.key); got != "value from ctxA" {
+			t.Errorf("%s: Value = %v, want value from ctxA", tt.name, got)
+		}
+	}
+}
+
+func TestWithValueIfAbsent(t *testing.T) {
+	ctxB, _ := mainChain()
+
+	// absent
+	ctx := WithValueIfAbsent(ctxB, "keyC", "value from ctxC")
+	if got := ctx.Value("keyC"); got != "value from ctxC" {
+		t.Errorf("absent: Value(keyC) = %v, want value from ctxC", got)
+	}
+
+	// present: parent is returned as is
+	if ctx := WithValueIfAbsent(ctxB, "keyA", "other"); ctx != ctxB {
+		t.Errorf("present: got a new context, want ctxB; Value(keyA) = %v", ctx.Value("keyA"))
+	}
+
+	// explicitly nil counts as absent
+	withNil := context.WithValue(ctxB, "keyN", nil)
+	if got := WithValueIfAbsent(withNil, "keyN", "set").Value("keyN"); got != "set" {
+		t.Errorf("nil: Value(keyN) = %v, want set", got)
+	}
+}
@@ -0,0 +1,34 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrWaitTimeout is returned by WaitTimeout when it gives up before the
+// context is done.
+var ErrWaitTimeout = errors.New("ctxutil: wait timed out")
+
+// Wait blocks until ctx is done and returns ctx.Err(). For a context that
+// can never be canceled, such as context.Background(), it blocks forever.
+func Wait(ctx context.Context) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+// WaitTimeout is like Wait, but gives up after d and returns ErrWaitTimeout.
+// ctx itself is left alone.
+func WaitTimeout(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return ErrWaitTimeout
+	}
+}
@@ -0,0 +1,45 @@
+package ctxutil
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWait(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait after cancel = %v, want context.Canceled", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait after the deadline = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestWaitTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+	if err := WaitTimeout(ctx, time.Second); !errors.Is(err, context.Canceled) {
+		t.Errorf("WaitTimeout after cancel = %v, want context.Canceled", err)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := WaitTimeout(ctx, time.Second); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitTimeout after the deadline = %v, want context.DeadlineExceeded", err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	if err := WaitTimeout(ctx, 10*time.Millisecond); err != ErrWaitTimeout {
+		t.Errorf("WaitTimeout on a live ctx = %v, want ErrWaitTimeout", err)
+	}
+	if ctx.Err() != nil {
+		t.Errorf("ctx.Err() = %v after WaitTimeout, want nil", ctx.Err())
+	}
+}
@@ -0,0 +1,190 @@
+package ctxutil
+
+import (
+	"context"
+	"reflect"
+	"unsafe"
+)
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// layer is a single node of a context chain as seen through reflection.
+type layer struct {
+	ctx context.Context
+
+	// kind is the name of a type from the context package, for example
+	// "valueCtx" or "cancelCtx". Background and TODO are reported as
+	// "emptyCtx". It is empty for types defined elsewhere.
+	kind string
+
+	// isValue is set for nodes that hold a key/value pair of their own:
+	// valueCtx, and the lazy nodes of WithLazy.
+	isValue bool
+	key     any
+	val     any
+	lazy    *lazyCtx // set for WithLazy, whose val is computed on demand
+
+	// fields holds the pairs of a WithFields node.
+	fields *Fields
+
+	// parent is the wrapped context, or nil at the root and for types
+	// whose parent can not be found.
+	parent context.Context
+
+	// next lists, in order, the contexts Value falls back to after this
+	// node, for nodes with more than one parent like MergeContexts and
+	// WithFallback. It is nil when Value only falls back to parent.
+	next []context.Context
+}
+
+// pair is a key/value pair held by a layer.
+type pair struct {
+	key  any
+	val  any
+	lazy *lazyCtx
+}
+
+// value returns the value of p, running the compute func of a lazy pair
+// if it has not run yet.
+func (p pair) value() any {
+	if p.lazy != nil {
+		return p.lazy.Value(p.key)
+	}
+	return p.val
+}
+
+// pairs calls yield for each pair held by l itself, until yield returns
+// false.
+func (l layer) pairs(yield func(pair) bool) bool {
+	if l.isValue && !yield(pair{key: l.key, val: l.val, lazy: l.lazy}) {
+		return false
+	}
+	if f := l.fields; f != nil {
+		for i := 0; i < f.n; i++ {
+			if !yield(pair{key: f.keys[i], val: f.vals[i]}) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// inspect reads the unexported fields of ctx. Besides the types of the
+// context package, it knows the value-carrying types of this package.
+// Other types produce a layer with no kind; they are still followed if they
+// embed a context.Context.
+func inspect(ctx context.Context) layer {
+	l := layer{ctx: ctx}
+	switch c := ctx.(type) {
+	case *lazyCtx:
+		l.parent = c.Context
+		l.isValue, l.key, l.lazy = true, c.key, c
+		return l
+	case *fieldsCtx:
+		l.parent = c.Context
+		l.fields = &c.fields
+		return l
+	case *mergeCtx:
+		l.next = c.parents
+		return l
+	case *fallbackCtx:
+		l.parent = c.Context
+		l.next = []context.Context{c.Context, c.fallback}
+		return l
+	}
+
+	v := reflect.ValueOf(ctx)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return l
+		}
+		v = v.Elem()
+	} else {
+		// copy the value so its fields become addressable
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		v = p.Elem()
+	}
+
+	t := v.Type()
+	if t.PkgPath() == "context" {
+		l.kind = t.Name()
+		switch l.kind {
+		case "emptyCtx", "backgroundCtx", "todoCtx":
+			l.kind = "emptyCtx"
+			return l
+		}
+	}
+	if v.Kind() != reflect.Struct {
+		return l
+	}
+
+	if f, ok := field(v, "Context"); ok && f.Type() == contextType {
+		l.parent, _ = f.Interface().(context.Context)
+	} else if f, ok := field(v, "c"); ok && l.kind == "withoutCancelCtx" {
+		l.parent, _ = f.Interface().(context.Context)
+	}
+
+	if l.kind == "valueCtx" {
+		key, _ := field(v, "key")
+		val, _ := field(v, "val")
+		l.isValue = true
+		l.key = key.Interface()
+		l.val = val.Interface()
+	}
+	return l
+}
+
+// field returns the named field of the addressable struct v, with the
+// read-only flag of unexported fields stripped.
+func field(v reflect.Value, name string) (reflect.Value, bool) {
+	sf, ok := v.Type().FieldByName(name)
+	if !ok {
+		return reflect.Value{}, false
+	}
+	f, err := v.FieldByIndexErr(sf.Index)
+	if err != nil {
+		return reflect.Value{}, false
+	}
+	return reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem(), true
+}
+
+// walk calls fn for ctx and then each of its ancestors, until fn returns
+// false or the chain ends. depth is 0 for ctx itself.
+func walk(ctx context.Context, fn func(depth int, l layer) bool) {
+	for depth := 0; ctx != nil; depth++ {
+		l := inspect(ctx)
+		if !fn(depth, l) {
+			return
+		}
+		ctx = l.parent
+	}
+}
+
+// lookup calls fn for every context that ctx.Value may consult, in the
+// order it consults them, until fn returns false. Unlike walk it follows
+// every parent of nodes like MergeContexts, one whole chain after the
+// other. depth is the number of hops from ctx.
+func lookup(ctx context.Context, fn func(depth int, l layer) bool) {
+	lookupFrom(ctx, 0, fn)
+}
+
+func lookupFrom(ctx context.Context, depth int, fn func(depth int, l layer) bool) bool {
+	for ; ctx != nil; depth++ {
+		l := inspect(ctx)
+		if !fn(depth, l) {
+			return false
+		}
+		if l.next == nil {
+			ctx = l.parent
+			continue
+		}
+		for _, p := range l.next {
+			if !lookupFrom(p, depth+1, fn) {
+				return false
+			}
+		}
+		return true
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+// Package errgroup runs a group of goroutines tied to a context and
+// collects the first error, in the style of golang.org/x/sync/errgroup.
+package errgroup
+
+import (
+	"context"
+	"sync"
+)
+
+// A Group is a collection of goroutines working on the same task.
+// It must be created with WithContext.
+type Group struct {
+	cancel context.CancelFunc
+
+	wg sync.WaitGroup
+
+	errOnce sync.Once
+	err     error
+}
+
+// WithContext returns a new Group and a context derived from parent. The
+// context is canceled the first time a function passed to Go returns a
+// non-nil error, or when Wait returns, whichever happens first.
+func WithContext(parent context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancel(parent)
+	return &Group{cancel: cancel}, ctx
+}
+
+// Go calls fn in a new goroutine. The first non-nil error cancels the
+// group's context and is returned by Wait.
+func (g *Group) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		if err := fn(); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				g.cancel()
+			})
+		}
+	}()
+}
+
+// Wait blocks until every function passed to Go has returned, then returns
+// the first non-nil error, if any. The group's context is always canceled
+// by the time Wait returns.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,76 @@
+// Package metadata carries multi-valued, header-like request metadata in a
+// context, in the style of gRPC metadata.
+package metadata
+
+import (
+	"context"
+	"strings"
+)
+
+// MD maps lowercase keys to their values.
+type MD map[string][]string
+
+// Get returns the values of key.
+func (md MD) Get(key string) []string {
+	return md[strings.ToLower(key)]
+}
+
+// Copy returns a deep copy of md with every key lowercased. Values of keys
+// that only differ in case are merged.
+func (md MD) Copy() MD {
+	out := make(MD, len(md))
+	for k, vals := range md {
+		k = strings.ToLower(k)
+		out[k] = append(out[k], vals...)
+	}
+	return out
+}
+
+type (
+	incomingKey struct{}
+	outgoingKey struct{}
+)
+
+// NewIncoming returns a child of ctx carrying md as the metadata received
+// with a request. md is copied, so later changes to it are not seen.
+func NewIncoming(ctx context.Context, md MD) context.Context {
+	return context.WithValue(ctx, incomingKey{}, md.Copy())
+}
+
+// FromIncoming returns the metadata attached by NewIncoming. The MD held
+// by ctx must not be modified, so a copy is returned.
+func FromIncoming(ctx context.Context) (MD, bool) {
+	md, ok := ctx.Value(incomingKey{}).(MD)
+	if !ok {
+		return nil, false
+	}
+	return md.Copy(), true
+}
+
+// NewOutgoing returns a child of ctx carrying md as the metadata to send
+// with outgoing requests, replacing any inherited one. md is copied.
+func NewOutgoing(ctx context.Context, md MD) context.Context {
+	return context.WithValue(ctx, outgoingKey{}, md.Copy())
+}
+
+// FromOutgoing returns a copy of the outgoing metadata of ctx.
+func FromOutgoing(ctx context.Context) (MD, bool) {
+	md, ok := ctx.Value(outgoingKey{}).(MD)
+	if !ok {
+		return nil, false
+	}
+	return md.Copy(), true
+}
+
+// AppendToOutgoing returns a child of ctx whose outgoing metadata has vals
+// appended to key. The MD held by ctx is left untouched; the child gets an
+// updated copy.
+func AppendToOutgoing(ctx context.Context, key string, vals ...string) context.Context {
+	md, _ := FromOutgoing(ctx)
+	if md == nil {
+		md = MD{}
+	}
+	key = strings.ToLower(key)
+	md[key] = append(md[key], vals...)
+	return context.WithValue(ctx, outgoingKey{}, md)
+}
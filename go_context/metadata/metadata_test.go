@@ -0,0 +1,50 @@
+package metadata
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestAppendToOutgoing(t *testing.T) {
+	parent := NewOutgoing(context.Background(), MD{"trace-id": {"1"}})
+	child := AppendToOutgoing(parent, "trace-id", "2")
+
+	md, _ := FromOutgoing(parent)
+	if got := md.Get("trace-id"); !slices.Equal(got, []string{"1"}) {
+		t.Errorf("parent trace-id = %v, want [1]", got)
+	}
+	md, _ = FromOutgoing(child)
+	if got := md.Get("trace-id"); !slices.Equal(got, []string{"1", "2"}) {
+		t.Errorf("child trace-id = %v, want [1 2]", got)
+	}
+
+	// a second child of the same parent does not see the first one's value
+	other := AppendToOutgoing(parent, "trace-id", "3")
+	md, _ = FromOutgoing(other)
+	if got := md.Get("trace-id"); !slices.Equal(got, []string{"1", "3"}) {
+		t.Errorf("other child trace-id = %v, want [1 3]", got)
+	}
+}
+
+func TestLowercaseKeys(t *testing.T) {
+	ctx := NewIncoming(context.Background(), MD{"Trace-ID": {"1"}, "trace-id": {"2"}})
+	md, ok := FromIncoming(ctx)
+	if !ok {
+		t.Fatal("no incoming metadata")
+	}
+	if _, ok := md["Trace-ID"]; ok {
+		t.Error("mixed case key kept")
+	}
+	got := md.Get("TRACE-ID")
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"1", "2"}) {
+		t.Errorf("Get(TRACE-ID) = %v, want [1 2]", got)
+	}
+
+	ctx = AppendToOutgoing(context.Background(), "X-User", "alice")
+	md, _ = FromOutgoing(ctx)
+	if got := md["x-user"]; !slices.Equal(got, []string{"alice"}) {
+		t.Errorf("x-user = %v, want [alice]", got)
+	}
+}
@@ -0,0 +1,93 @@
+// Package ratelimit provides a token bucket rate limiter whose Wait gives
+// up when its context is done.
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter hands out up to rate tokens per second, and lets up to burst of
+// them pile up while unused. It is safe for concurrent use.
+type Limiter struct {
+	rate  float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time // when tokens was last brought up to date
+}
+
+// NewLimiter returns a limiter that starts with a full bucket of burst
+// tokens. A rate <= 0 never adds tokens after the first burst.
+func NewLimiter(rate float64, burst int) *Limiter {
+	if burst < 1 {
+		panic("ratelimit: burst must be at least 1")
+	}
+	return &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Allow takes a token if one is available right now.
+func (l *Limiter) Allow() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	if l.tokens < 1 {
+		return false
+	}
+	l.tokens--
+	return true
+}
+
+// Wait takes a token, blocking until one is available or ctx is done. In
+// the latter case it returns ctx.Err() and no token is taken.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		l.mu.Lock()
+		now := time.Now()
+		l.refill(now)
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		missing := 1 - l.tokens
+		l.mu.Unlock()
+
+		if l.rate <= 0 {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+
+		// another waiter may take the token first, so look again once it
+		// should be there instead of assuming it is ours
+		t := time.NewTimer(time.Duration(missing / l.rate * float64(time.Second)))
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+}
+
+// refill adds the tokens earned since the last call. l.mu must be held.
+func (l *Limiter) refill(now time.Time) {
+	if l.rate > 0 {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
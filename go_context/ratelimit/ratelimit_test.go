@@ -0,0 +1,44 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitTimeout(t *testing.T) {
+	for _, rate := range []float64{0, 1} {
+		l := NewLimiter(rate, 1)
+		if !l.Allow() {
+			t.Fatalf("rate %v: first Allow = false", rate)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		err := l.Wait(ctx)
+		cancel()
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("rate %v: Wait = %v, want context.DeadlineExceeded", rate, err)
+		}
+
+		// no token was taken: the bucket only holds what refilling earned
+		l.mu.Lock()
+		l.refill(time.Now())
+		tokens := l.tokens
+		l.mu.Unlock()
+		if tokens < 0 || tokens >= 1 {
+			t.Errorf("rate %v: %v tokens after a failed Wait, want in [0, 1)", rate, tokens)
+		}
+	}
+}
+
+func TestWait(t *testing.T) {
+	l := NewLimiter(1000, 1)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	for i := 0; i < 3; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatalf("Wait #%d = %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+// Package singleflight coalesces concurrent calls for the same key into a
+// single execution, letting each caller stop waiting when its own context
+// is done.
+package singleflight
+
+import (
+	"context"
+	"sync"
+)
+
+// call is an execution in flight, or just finished.
+type call struct {
+	done chan struct{} // closed once val and err are set
+	val  any
+	err  error
+	dups int // callers that joined the run, under Group.mu
+}
+
+// Group runs at most one function per key at a time. The zero value is
+// ready to use.
+type Group struct {
+	mu sync.Mutex
+	m  map[string]*call
+}
+
+// Do runs fn for key, unless a run for key is already in flight, in which
+// case it waits for that run and returns its result.
+//
+// fn runs in its own goroutine on a context that keeps the values of the
+// first caller's ctx but not its cancellation, so no single caller can
+// cancel a run the others share. If a caller's ctx is done first, Do
+// returns ctx.Err() for that caller only, and the run goes on.
+func (g *Group) Do(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (any, error) {
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[string]*call)
+	}
+	c, ok := g.m[key]
+	if ok {
+		c.dups++
+	} else {
+		c = &call{done: make(chan struct{})}
+		g.m[key] = c
+		go g.run(context.WithoutCancel(ctx), key, c, fn)
+	}
+	g.mu.Unlock()
+
+	select {
+	case <-c.done:
+		return c.val, c.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (g *Group) run(ctx context.Context, key string, c *call, fn func(ctx context.Context) (any, error)) {
+	c.val, c.err = fn(ctx)
+
+	g.mu.Lock()
+	delete(g.m, key)
+	g.mu.Unlock()
+	close(c.done)
+}
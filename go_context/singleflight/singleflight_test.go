@@ -0,0 +1,59 @@
+package singleflight
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestDo is meant to be run with -race.
+func TestDo(t *testing.T) {
+	var g Group
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (any, error) {
+		calls.Add(1)
+		<-release
+		return "value from fn", nil
+	}
+
+	const n = 16
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+			v, err := g.Do(context.Background(), "key", fn)
+			if v != "value from fn" || err != nil {
+				t.Errorf("Do = %v, %v, want value from fn, nil", v, err)
+			}
+		}()
+	}
+	// wait until every caller but the first has joined the run
+	for {
+		g.mu.Lock()
+		c := g.m["key"]
+		joined := c != nil && c.dups == n-1
+		g.mu.Unlock()
+		if joined {
+			break
+		}
+		runtime.Gosched()
+	}
+
+	// a caller whose ctx is done returns early, while the run goes on
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := g.Do(ctx, "key", fn); !errors.Is(err, context.Canceled) {
+		t.Errorf("Do with a canceled ctx = %v, want context.Canceled", err)
+	}
+
+	close(release)
+	wg.Wait()
+	if got := calls.Load(); got != 1 {
+		t.Errorf("fn ran %d times, want 1", got)
+	}
+}
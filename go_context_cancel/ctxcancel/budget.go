@@ -0,0 +1,109 @@
+package ctxcancel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExceeded is the cause of a context from WithBudget once its
+// tracked calls have used up the budget.
+var ErrBudgetExceeded = errors.New("ctxcancel: time budget exceeded")
+
+// Budget is a fixed amount of time spread over a sequence of calls.
+type Budget struct {
+	cancel context.CancelCauseFunc
+
+	mu        sync.Mutex
+	remaining time.Duration
+	start     time.Time // start of the tracked call in progress, zero if none
+}
+
+// budgetCtx reports the deadline of its Budget.
+type budgetCtx struct {
+	context.Context
+	b *Budget
+}
+
+// WithBudget returns a child of parent and a Budget of total. Only the time
+// spent inside Budget.Track counts against it. Once the tracked time
+// reaches total, even in the middle of a call, the context is canceled with
+// ErrBudgetExceeded as its cause.
+//
+// The context's Deadline is the moment the remaining budget would run out
+// if it were all spent from now on, or from the start of the tracked call
+// in progress, or parent's deadline if that is sooner. Outside of Track it
+// moves along with the clock, as idle time is not charged.
+//
+// Budget.Stop must be called once the budget is no longer needed, as with
+// a CancelFunc.
+func WithBudget(parent context.Context, total time.Duration) (context.Context, *Budget) {
+	ctx, cancel := context.WithCancelCause(parent)
+	b := &Budget{cancel: cancel, remaining: total}
+	if total <= 0 {
+		cancel(ErrBudgetExceeded)
+	}
+	return &budgetCtx{Context: ctx, b: b}, b
+}
+
+func (c *budgetCtx) Deadline() (deadline time.Time, ok bool) {
+	c.b.mu.Lock()
+	if c.b.start.IsZero() {
+		deadline = time.Now().Add(c.b.remaining)
+	} else {
+		deadline = c.b.start.Add(c.b.remaining)
+	}
+	c.b.mu.Unlock()
+	if d, ok := c.Context.Deadline(); ok && d.Before(deadline) {
+		return d, true
+	}
+	return deadline, true
+}
+
+// Track runs fn and charges the time it takes to the budget. If the budget
+// is already used up, fn is not called and Track returns ErrBudgetExceeded.
+// Otherwise it returns what fn returns.
+//
+// Tracked calls are meant to run one after another; overlapping calls are
+// each allowed the whole remaining budget.
+func (b *Budget) Track(fn func() error) error {
+	b.mu.Lock()
+	remaining := b.remaining
+	if remaining <= 0 {
+		b.mu.Unlock()
+		return ErrBudgetExceeded
+	}
+	start := time.Now()
+	b.start = start
+	b.mu.Unlock()
+
+	t := time.AfterFunc(remaining, func() { b.cancel(ErrBudgetExceeded) })
+	err := fn()
+	elapsed := time.Since(start)
+	t.Stop()
+
+	b.mu.Lock()
+	b.remaining -= elapsed
+	b.start = time.Time{}
+	exceeded := b.remaining <= 0
+	b.mu.Unlock()
+	if exceeded {
+		b.cancel(ErrBudgetExceeded)
+	}
+	return err
+}
+
+// Remaining returns how much of the budget is left. It is not positive
+// once the budget is used up.
+func (b *Budget) Remaining() time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.remaining
+}
+
+// Stop cancels the budget's context, if it is not done yet, and releases
+// its resources.
+func (b *Budget) Stop() {
+	b.cancel(context.Canceled)
+}
@@ -0,0 +1,107 @@
+package ctxcancel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBudgetExceeded(t *testing.T) {
+	ctx, b := WithBudget(context.Background(), 30*time.Millisecond)
+	defer b.Stop()
+
+	op := func() error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}
+	for i := 0; i < 2; i++ {
+		if err := b.Track(op); err != nil {
+			t.Fatalf("Track #%d = %v, want nil", i, err)
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("ctx not done after the budget ran out")
+	}
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", ctx.Err())
+	}
+	if cause := Cause(ctx); !errors.Is(cause, ErrBudgetExceeded) {
+		t.Errorf("Cause() = %v, want ErrBudgetExceeded", cause)
+	}
+	if b.Remaining() > 0 {
+		t.Errorf("Remaining() = %v, want <= 0", b.Remaining())
+	}
+
+	ran := false
+	if err := b.Track(func() error { ran = true; return nil }); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Track after the budget = %v, want ErrBudgetExceeded", err)
+	}
+	if ran {
+		t.Error("fn ran after the budget was used up")
+	}
+}
+
+func TestBudgetMidCall(t *testing.T) {
+	ctx, b := WithBudget(context.Background(), 10*time.Millisecond)
+	defer b.Stop()
+
+	// the context is canceled while the call is still running
+	b.Track(func() error {
+		<-ctx.Done()
+		return nil
+	})
+	if cause := Cause(ctx); !errors.Is(cause, ErrBudgetExceeded) {
+		t.Errorf("Cause() = %v, want ErrBudgetExceeded", cause)
+	}
+}
+
+func TestBudgetDeadline(t *testing.T) {
+	ctx, b := WithBudget(context.Background(), 50*time.Millisecond)
+	defer b.Stop()
+
+	b.Track(func() error {
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+	time.Sleep(30 * time.Millisecond) // idle time is not charged
+
+	// outside of Track the deadline is now + remaining
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		t.Fatal("no deadline")
+	}
+	want := time.Now().Add(b.Remaining())
+	if diff := want.Sub(deadline); diff < 0 || diff > 5*time.Millisecond {
+		t.Errorf("Deadline() is %v off now + Remaining()", diff)
+	}
+	if !deadline.After(time.Now()) || ctx.Err() != nil {
+		t.Errorf("Deadline() = %v in the past, Err() = %v", time.Until(deadline), ctx.Err())
+	}
+
+	// during Track it is the start of the call + remaining, and stays put
+	b.Track(func() error {
+		d1, _ := ctx.Deadline()
+		time.Sleep(10 * time.Millisecond)
+		d2, _ := ctx.Deadline()
+		if !d1.Equal(d2) {
+			t.Errorf("Deadline() moved by %v during Track", d2.Sub(d1))
+		}
+		return nil
+	})
+}
+
+func TestBudgetParentDeadline(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	ctx, b := WithBudget(parent, time.Hour)
+	defer b.Stop()
+
+	want, _ := parent.Deadline()
+	if got, _ := ctx.Deadline(); !got.Equal(want) {
+		t.Errorf("Deadline() = %v, want the sooner parent deadline %v", got, want)
+	}
+}
@@ -0,0 +1,83 @@
+// Package ctxcancel provides helpers for canceling a context with a reason.
+package ctxcancel
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// CancelOnError returns a child of parent and a function that cancels it
+// with err as the cause. After cancel(err), ctx.Err() is context.Canceled
+// while Cause(ctx) is err. A nil err behaves like a plain cancel.
+func CancelOnError(parent context.Context) (ctx context.Context, cancel func(error)) {
+	c, cancelCause := context.WithCancelCause(parent)
+	return c, func(err error) { cancelCause(err) }
+}
+
+// Cause is context.Cause. It returns why ctx was canceled, or nil if it has
+// not been canceled yet.
+func Cause(ctx context.Context) error {
+	return context.Cause(ctx)
+}
+
+// WithTimeoutCause is like context.WithTimeout, but once the timeout fires
+// Cause(ctx) reports cause instead of context.DeadlineExceeded. That tells a
+// timed out DB query apart from a timed out HTTP call, although ctx.Err()
+// is the same for both.
+//
+// If cancel is called before the timeout, Cause(ctx) wraps
+// context.Canceled and mentions cause.
+func WithTimeoutCause(parent context.Context, d time.Duration, cause error) (ctx context.Context, cancel context.CancelFunc) {
+	outer, cancelOuter := context.WithCancelCause(parent)
+	ctx, stop := context.WithTimeoutCause(outer, d, cause)
+	return ctx, func() {
+		cancelOuter(fmt.Errorf("%w before timeout (%v)", context.Canceled, cause))
+		stop()
+	}
+}
+
+type guardConfig struct {
+	swallow bool
+}
+
+// GuardOption configures Guard.
+type GuardOption func(*guardConfig)
+
+// Swallow makes Guard stop the panic after canceling, instead of panicking
+// again.
+func Swallow() GuardOption {
+	return func(c *guardConfig) { c.swallow = true }
+}
+
+// Guard returns a func to be deferred at the top of a worker goroutine:
+//
+//	defer ctxcancel.Guard(ctx, cancel)()
+//
+// If the goroutine panics, the panic is recovered and passed to cancelCause
+// as an error, so that goroutines sharing ctx stop and Cause(ctx) tells
+// why. A recovered value that is not an error becomes
+// fmt.Errorf("panic: %v", r). The panic then goes on, unless Swallow is
+// given. If ctx is already done, its cause is left as it is.
+func Guard(ctx context.Context, cancelCause func(error), opts ...GuardOption) func() {
+	var cfg guardConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return func() {
+		r := recover()
+		if r == nil {
+			return
+		}
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("panic: %v", r)
+		}
+		if ctx.Err() == nil {
+			cancelCause(err)
+		}
+		if !cfg.swallow {
+			panic(r)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package ctxcancel
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errDBDown = errors.New("db is down")
+
+func TestCancelOnError(t *testing.T) {
+	ctx, cancel := CancelOnError(context.Background())
+	child, childCancel := context.WithCancel(ctx)
+	defer childCancel()
+
+	cancel(errDBDown)
+
+	for name, c := range map[string]context.Context{"ctx": ctx, "child": child} {
+		if !errors.Is(c.Err(), context.Canceled) {
+			t.Errorf("%s.Err() = %v, want context.Canceled", name, c.Err())
+		}
+		if cause := Cause(c); cause != errDBDown {
+			t.Errorf("Cause(%s) = %v, want %v", name, cause, errDBDown)
+		}
+	}
+}
+
+func TestWithTimeoutCause(t *testing.T) {
+	ctx, cancel := WithTimeoutCause(context.Background(), 10*time.Millisecond, errDBDown)
+	defer cancel()
+
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("Err() = %v, want context.DeadlineExceeded", ctx.Err())
+	}
+	if cause := Cause(ctx); cause != errDBDown {
+		t.Errorf("Cause() = %v, want %v", cause, errDBDown)
+	}
+}
+
+func TestWithTimeoutCauseEarlyCancel(t *testing.T) {
+	ctx, cancel := WithTimeoutCause(context.Background(), time.Hour, errDBDown)
+	cancel()
+
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", ctx.Err())
+	}
+	cause := Cause(ctx)
+	if !errors.Is(cause, context.Canceled) {
+		t.Errorf("Cause() = %v, want it to wrap context.Canceled", cause)
+	}
+	if errors.Is(cause, errDBDown) {
+		t.Errorf("Cause() = %v, want it not to wrap the timeout cause", cause)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []GuardOption
+		repanic bool
+	}{
+		{"repanic", nil, true},
+		{"swallow", []GuardOption{Swallow()}, false},
+	}
+	for _, tt := range tests {
+		ctx, cancel := CancelOnError(context.Background())
+
+		var recovered any
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			defer func() { recovered = recover() }() // catches a repanic
+			defer Guard(ctx, cancel, tt.opts...)()
+			panic("worker failed")
+		}()
+		<-done
+
+		if (recovered != nil) != tt.repanic {
+			t.Errorf("%s: repanicked with %v, want repanic %v", tt.name, recovered, tt.repanic)
+		}
+		if tt.repanic && recovered != "worker failed" {
+			t.Errorf("%s: repanicked with %v, want worker failed", tt.name, recovered)
+		}
+		if ctx.Err() == nil {
+			t.Fatalf("%s: shared ctx not canceled", tt.name)
+		}
+		if cause := Cause(ctx); cause == nil || cause.Error() != "panic: worker failed" {
+			t.Errorf("%s: Cause() = %v, want panic: worker failed", tt.name, cause)
+		}
+	}
+}
+
+func TestGuardErrorValue(t *testing.T) {
+	ctx, cancel := CancelOnError(context.Background())
+	func() {
+		defer Guard(ctx, cancel, Swallow())()
+		panic(errDBDown)
+	}()
+	if cause := Cause(ctx); cause != errDBDown {
+		t.Errorf("Cause() = %v, want %v", cause, errDBDown)
+	}
+}
@@ -0,0 +1,3 @@
+module go_context_cancel
+
+go 1.21
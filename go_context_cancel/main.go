@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go_context_cancel/ctxcancel"
+)
+
+var errDBDown = errors.New("db is down")
+
+func main() {
+	ctx, cancel := ctxcancel.CancelOnError(context.Background())
+
+	child, childCancel := context.WithCancel(ctx) // child ctx of ctx
+	defer childCancel()
+
+	cancel(errDBDown)
+
+	fmt.Println(ctx.Err())              // context canceled
+	fmt.Println(ctxcancel.Cause(ctx))   // db is down
+	fmt.Println(child.Err())            // parent's err is propagated
+	fmt.Println(ctxcancel.Cause(child)) // and so is the cause
+	fmt.Println("==============")
+
+	budgetCtx, budget := ctxcancel.WithBudget(context.Background(), 30*time.Millisecond)
+	defer budget.Stop()
+
+	for i := 0; i < 3; i++ {
+		err := budget.Track(func() error {
+			time.Sleep(20 * time.Millisecond) // a query
+			return nil
+		})
+		fmt.Println(i, err) // the third call is not run
+	}
+	fmt.Println(budgetCtx.Err())            // context canceled
+	fmt.Println(ctxcancel.Cause(budgetCtx)) // ctxcancel: time budget exceeded
+}